@@ -0,0 +1,138 @@
+package vhpackage
+
+import (
+	"errors"
+	"sort"
+)
+
+// BedSpawnRadius is the distance within which the game looks for a bed
+// owned by the player around a custom spawn point. Without a bed, the
+// player spawns at the world start.
+const BedSpawnRadius = 1
+
+var (
+	bedOwnerHash     = StableHashCode("owner")
+	bedOwnerNameHash = StableHashCode("ownerName")
+)
+
+// Bed is a bed placed in the world.
+type Bed struct {
+	UID       ZDOID   `json:"uid"`
+	Owner     int64   `json:"owner"`
+	OwnerName string  `json:"owner_name,omitempty"`
+	Position  Vector3 `json:"position"`
+}
+
+// PlayerBeds describes the beds and spawn point of a player in a world.
+type PlayerBeds struct {
+	ID                   int64   `json:"id"`
+	Name                 string  `json:"name"`
+	HaveCustomSpawnPoint bool    `json:"have_custom_spawn_point"`
+	SpawnPoint           Vector3 `json:"spawn_point"`
+	// SpawnBed is the bed of the player found at the custom spawn point.
+	SpawnBed *Bed `json:"spawn_bed,omitempty"`
+	// Beds lists the beds owned by the player.
+	Beds []*Bed `json:"beds"`
+}
+
+// MissingSpawnBed reports whether the player has a custom spawn point
+// without a bed, and will spawn at the world start.
+func (p *PlayerBeds) MissingSpawnBed() bool {
+	return p.HaveCustomSpawnPoint && p.SpawnBed == nil
+}
+
+// BedAudit cross-checks the beds of a world with player profiles.
+type BedAudit struct {
+	Players []*PlayerBeds `json:"players"`
+	// UnknownBeds lists beds owned by players without a profile.
+	UnknownBeds []*Bed `json:"unknown_beds"`
+}
+
+// Beds returns the beds of the world, sorted by ZDOID.
+func (w *World) Beds() []*Bed {
+	beds := []*Bed{}
+	for _, zdo := range w.ZDOs {
+		if zdo.Category() != CategoryBed {
+			continue
+		}
+
+		beds = append(beds, &Bed{
+			UID:       zdo.UID,
+			Owner:     zdo.GetLongHash(bedOwnerHash, 0),
+			OwnerName: zdo.GetStringHash(bedOwnerNameHash, ""),
+			Position:  zdo.Position,
+		})
+	}
+
+	sort.Slice(beds, func(i, j int) bool { return beds[i].UID.Less(beds[j].UID) })
+	return beds
+}
+
+// AuditBeds reports the beds and spawn point of each profile that visited
+// the world or owns a bed in it, and the beds of unknown players.
+// Unclaimed beds are not reported. The world metadata must be loaded.
+func (w *World) AuditBeds(profiles []*PlayerProfile) (*BedAudit, error) {
+	if w.Metadata == nil {
+		return nil, errors.New("world metadata is required")
+	}
+
+	beds := w.Beds()
+	byOwner := make(map[int64][]*Bed)
+	for _, bed := range beds {
+		byOwner[bed.Owner] = append(byOwner[bed.Owner], bed)
+	}
+
+	audit := &BedAudit{
+		Players:     []*PlayerBeds{},
+		UnknownBeds: []*Bed{},
+	}
+	known := make(map[int64]bool, len(profiles))
+	for _, p := range profiles {
+		known[p.ID] = true
+
+		data, visited := p.WorldData[w.Metadata.UID]
+		if !visited && len(byOwner[p.ID]) == 0 {
+			continue
+		}
+
+		pb := &PlayerBeds{
+			ID:                   p.ID,
+			Name:                 p.Name,
+			HaveCustomSpawnPoint: data.HaveCustomSpawnPoint,
+			SpawnPoint:           data.SpawnPoint,
+			Beds:                 byOwner[p.ID],
+		}
+		if pb.Beds == nil {
+			pb.Beds = []*Bed{}
+		}
+		if pb.HaveCustomSpawnPoint {
+			pb.SpawnBed = findBedNearby(pb.Beds, pb.SpawnPoint)
+		}
+		audit.Players = append(audit.Players, pb)
+	}
+
+	for _, bed := range beds {
+		if bed.Owner != 0 && !known[bed.Owner] {
+			audit.UnknownBeds = append(audit.UnknownBeds, bed)
+		}
+	}
+
+	return audit, nil
+}
+
+// findBedNearby returns the closest bed closer than BedSpawnRadius to point.
+// The game only spawns players in beds they own, so beds must be those of
+// the player.
+func findBedNearby(beds []*Bed, point Vector3) *Bed {
+	var closest *Bed
+	var closestDist float32 = BedSpawnRadius * BedSpawnRadius
+	for _, bed := range beds {
+		dx := bed.Position.X - point.X
+		dy := bed.Position.Y - point.Y
+		dz := bed.Position.Z - point.Z
+		if dist := dx*dx + dy*dy + dz*dz; dist < closestDist {
+			closest, closestDist = bed, dist
+		}
+	}
+	return closest
+}
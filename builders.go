@@ -0,0 +1,114 @@
+package vhpackage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// BuilderStats summarizes the pieces built by a player.
+type BuilderStats struct {
+	CreatorID int64  `json:"creator_id"`
+	Name      string `json:"name,omitempty"`
+	Pieces    int    `json:"pieces"`
+	// Min and Max delimit the area containing the pieces (X, Z).
+	Min Vector2 `json:"min"`
+	Max Vector2 `json:"max"`
+
+	FirstTimeCreated int64 `json:"first_time_created"`
+	LastTimeCreated  int64 `json:"last_time_created"`
+	// FirstDay and LastDay are the in-game days of the first and last pieces.
+	FirstDay int `json:"first_day"`
+	LastDay  int `json:"last_day"`
+}
+
+// Area returns the size of the area containing the pieces, in square meters.
+func (s *BuilderStats) Area() float32 {
+	return (s.Max.X - s.Min.X) * (s.Max.Y - s.Min.Y)
+}
+
+// BuilderStats groups player built pieces by creator, most pieces first.
+// Creator names are resolved with the given profiles.
+func (w *World) BuilderStats(profiles []*PlayerProfile) []*BuilderStats {
+	names := make(map[int64]string, len(profiles))
+	for _, p := range profiles {
+		names[p.ID] = p.Name
+	}
+
+	byCreator := make(map[int64]*BuilderStats)
+	for _, zdo := range w.ZDOs {
+		creator, ok := zdo.LookupLongHash(creatorHash)
+		if !ok {
+			continue
+		}
+
+		x, z := zdo.Position.X, zdo.Position.Z
+		s, ok := byCreator[creator]
+		if !ok {
+			s = &BuilderStats{
+				CreatorID: creator,
+				Name:      names[creator],
+				Min:       Vector2{x, z},
+				Max:       Vector2{x, z},
+
+				FirstTimeCreated: zdo.TimeCreated,
+			}
+			byCreator[creator] = s
+		}
+
+		s.Pieces++
+		if x < s.Min.X {
+			s.Min.X = x
+		}
+		if z < s.Min.Y {
+			s.Min.Y = z
+		}
+		if x > s.Max.X {
+			s.Max.X = x
+		}
+		if z > s.Max.Y {
+			s.Max.Y = z
+		}
+		if zdo.TimeCreated < s.FirstTimeCreated {
+			s.FirstTimeCreated = zdo.TimeCreated
+		}
+		if zdo.TimeCreated > s.LastTimeCreated {
+			s.LastTimeCreated = zdo.TimeCreated
+		}
+	}
+
+	stats := make([]*BuilderStats, 0, len(byCreator))
+	for _, s := range byCreator {
+		s.FirstDay = GameDay(TicksDuration(s.FirstTimeCreated))
+		s.LastDay = GameDay(TicksDuration(s.LastTimeCreated))
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Pieces != stats[j].Pieces {
+			return stats[i].Pieces > stats[j].Pieces
+		}
+		return stats[i].CreatorID < stats[j].CreatorID
+	})
+
+	return stats
+}
+
+// NewPlayerProfilesFromDir reads every player profile (.fch) of dir.
+func NewPlayerProfilesFromDir(dir string) ([]*PlayerProfile, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.fch"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	profiles := make([]*PlayerProfile, 0, len(files))
+	for _, file := range files {
+		p, err := NewPlayerProfileFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load %s: %w", file, err)
+		}
+		profiles = append(profiles, p)
+	}
+
+	return profiles, nil
+}
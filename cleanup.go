@@ -0,0 +1,84 @@
+package vhpackage
+
+// ZDOIssue describes why a ZDO should be cleaned up.
+//
+// ZDOs owned by peers that will never reconnect are not an issue of saved
+// worlds: peer IDs are random for each session and the game clears the
+// owner of every ZDO when it loads a world.
+type ZDOIssue string
+
+const (
+	// IssueNotPersistent is a non persistent ZDO that was saved anyway.
+	IssueNotPersistent ZDOIssue = "not_persistent"
+	// IssueDuplicate is a ZDO with the same prefab and position as another one.
+	IssueDuplicate ZDOIssue = "duplicate"
+)
+
+// ZDOIssueEntry is an issue found on a ZDO.
+type ZDOIssueEntry struct {
+	Issue ZDOIssue   `json:"issue"`
+	ZDO   ZDOSummary `json:"zdo"`
+}
+
+// FindZDOIssues lists non persistent and duplicate ZDOs.
+// For duplicates, the first ZDO is not reported.
+func (w *World) FindZDOIssues() []ZDOIssueEntry {
+	type prefabPosition struct {
+		prefab   int
+		position Vector3
+	}
+	seen := make(map[prefabPosition]bool)
+
+	var issues []ZDOIssueEntry
+	for _, zdo := range w.ZDOs {
+		summary := ZDOSummary{UID: zdo.UID, Prefab: zdo.Prefab, Sector: zdo.Sector, Position: zdo.Position}
+
+		if !zdo.Persistent {
+			issues = append(issues, ZDOIssueEntry{Issue: IssueNotPersistent, ZDO: summary})
+		}
+
+		key := prefabPosition{zdo.Prefab, zdo.Position}
+		if seen[key] {
+			issues = append(issues, ZDOIssueEntry{Issue: IssueDuplicate, ZDO: summary})
+		}
+		seen[key] = true
+	}
+
+	return issues
+}
+
+// SummarizeZDOIssues groups issues with the key returned by group and
+// counts them by issue.
+func SummarizeZDOIssues(issues []ZDOIssueEntry, group func(ZDOSummary) string) map[string]map[ZDOIssue]int {
+	summary := make(map[string]map[ZDOIssue]int)
+	for _, i := range issues {
+		key := group(i.ZDO)
+		if summary[key] == nil {
+			summary[key] = make(map[ZDOIssue]int)
+		}
+		summary[key][i.Issue]++
+	}
+	return summary
+}
+
+// CleanupZDOs removes the ZDOs of the given issues and returns the number
+// of removed ZDOs.
+func (w *World) CleanupZDOs(issues []ZDOIssueEntry) int {
+	remove := make(map[ZDOID]bool)
+	for _, i := range issues {
+		remove[i.ZDO.UID] = true
+	}
+
+	removed := 0
+	zdos := w.ZDOs[:0]
+	for _, zdo := range w.ZDOs {
+		if remove[zdo.UID] {
+			removed++
+			continue
+		}
+		zdos = append(zdos, zdo)
+	}
+	w.ZDOs = zdos
+
+	return removed
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "output changes as JSON")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Fatalf("usage: %s diff [-json] old.fch new.fch", os.Args[0])
+	}
+
+	oldProfile, err := vhpackage.NewPlayerProfileFromFile(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to load player save %s: %s", fs.Arg(0), err)
+	}
+	newProfile, err := vhpackage.NewPlayerProfileFromFile(fs.Arg(1))
+	if err != nil {
+		log.Fatalf("Failed to load player save %s: %s", fs.Arg(1), err)
+	}
+
+	changes := vhpackage.DiffProfiles(oldProfile, newProfile)
+	if *jsonOutput {
+		if changes == nil {
+			changes = []vhpackage.Change{}
+		}
+		printJSON(changes)
+		return
+	}
+
+	for _, c := range changes {
+		fmt.Fprintln(os.Stdout, c)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+// editFunc applies an edit to a profile, given the arguments following the save file.
+type editFunc func(p *vhpackage.PlayerProfile, args []string) error
+
+type editCommand struct {
+	usage string
+	args  int // minimum number of arguments after the save file
+	setup func(fs *flag.FlagSet) editFunc
+}
+
+// editCommands lists the subcommands editing a profile. Each of them loads
+// the save file, applies the edit and writes the profile back, keeping the
+// previous file as a .bak backup.
+var editCommands = map[string]editCommand{
+	"set-skill": {
+		usage: "save.fch skill level",
+		args:  2,
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				player, err := playerData(p)
+				if err != nil {
+					return err
+				}
+				skill, err := vhpackage.ParseSkillType(args[0])
+				if err != nil {
+					return err
+				}
+				level, err := strconv.ParseFloat(args[1], 32)
+				if err != nil {
+					return fmt.Errorf("invalid level: %w", err)
+				}
+				return player.SetSkillLevel(skill, float32(level))
+			}
+		},
+	},
+	"give": {
+		usage: "[-quality n] [-durability d] save.fch item [count]",
+		args:  1,
+		setup: func(fs *flag.FlagSet) editFunc {
+			quality := fs.Int("quality", 1, "item quality")
+			durability := fs.Float64("durability", 100, "item durability")
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				player, err := playerData(p)
+				if err != nil {
+					return err
+				}
+				count, err := countArg(args, 1)
+				if err != nil {
+					return err
+				}
+				return player.AddItem(&vhpackage.Item{
+					Name:       args[0],
+					Stack:      count,
+					Durability: float32(*durability),
+					Quality:    *quality,
+				})
+			}
+		},
+	},
+	"take": {
+		usage: "save.fch item [count]",
+		args:  1,
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				player, err := playerData(p)
+				if err != nil {
+					return err
+				}
+				count, err := countArg(args, 0)
+				if err != nil {
+					return err
+				}
+				if player.RemoveItem(args[0], count) == 0 {
+					return fmt.Errorf("no %s in inventory", args[0])
+				}
+				return nil
+			}
+		},
+	},
+	"learn-recipe": {
+		usage: "save.fch recipe...",
+		args:  1,
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				player, err := playerData(p)
+				if err != nil {
+					return err
+				}
+				for _, name := range args {
+					player.AddKnownRecipe(name)
+				}
+				return nil
+			}
+		},
+	},
+	"learn-material": {
+		usage: "save.fch material...",
+		args:  1,
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				player, err := playerData(p)
+				if err != nil {
+					return err
+				}
+				for _, name := range args {
+					player.AddKnownMaterial(name)
+				}
+				return nil
+			}
+		},
+	},
+	"reset-power": {
+		usage: "save.fch",
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				player, err := playerData(p)
+				if err != nil {
+					return err
+				}
+				player.ResetGuardianPowerCooldown()
+				return nil
+			}
+		},
+	},
+	"set-spawn": {
+		usage: "save.fch world_uid|world.fwl x y z",
+		args:  4,
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				world, pos, err := worldPointArgs(args)
+				if err != nil {
+					return err
+				}
+				p.SetSpawnPoint(world, pos)
+				return nil
+			}
+		},
+	},
+	"set-home": {
+		usage: "save.fch world_uid|world.fwl x y z",
+		args:  4,
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				world, pos, err := worldPointArgs(args)
+				if err != nil {
+					return err
+				}
+				p.SetHomePoint(world, pos)
+				return nil
+			}
+		},
+	},
+	"rename": {
+		usage: "save.fch name",
+		args:  1,
+		setup: func(fs *flag.FlagSet) editFunc {
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				if args[0] == "" {
+					return errors.New("empty name")
+				}
+				p.Name = args[0]
+				return nil
+			}
+		},
+	},
+	"move-world": {
+		usage: "[-copy] [-force] save.fch from to",
+		args:  2,
+		setup: func(fs *flag.FlagSet) editFunc {
+			copyData := fs.Bool("copy", false, "keep the data of the source world")
+			force := fs.Bool("force", false, "replace existing data of the target world")
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				from, err := worldUIDArg(args[0])
+				if err != nil {
+					return err
+				}
+				to, err := worldUIDArg(args[1])
+				if err != nil {
+					return err
+				}
+				if *copyData {
+					return p.CopyWorldData(from, to, *force)
+				}
+				return p.MoveWorldData(from, to, *force)
+			}
+		},
+	},
+	"appearance": {
+		usage: "[-beard name] [-hair name] [-skin r,g,b] save.fch",
+		setup: func(fs *flag.FlagSet) editFunc {
+			beard := fs.String("beard", "", "beard item name")
+			hair := fs.String("hair", "", "hair item name")
+			skin := fs.String("skin", "", "skin color as r,g,b")
+			return func(p *vhpackage.PlayerProfile, args []string) error {
+				player, err := playerData(p)
+				if err != nil {
+					return err
+				}
+				if *beard != "" {
+					player.Beard = *beard
+				}
+				if *hair != "" {
+					player.Hair = *hair
+				}
+				if *skin != "" {
+					player.SkinColor, err = parseVector3(strings.Split(*skin, ","))
+					if err != nil {
+						return fmt.Errorf("invalid skin color: %w", err)
+					}
+				}
+				return nil
+			}
+		},
+	},
+}
+
+func runEdit(name string, cmd editCommand, args []string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	edit := cmd.setup(fs)
+	fs.Parse(args)
+
+	if fs.NArg() < 1+cmd.args {
+		log.Fatalf("usage: %s %s %s", os.Args[0], name, cmd.usage)
+	}
+
+	savePath := fs.Arg(0)
+	profile, err := vhpackage.NewPlayerProfileFromFile(savePath)
+	if err != nil {
+		log.Fatalf("Failed to load player save: %s", err)
+	}
+
+	if err := edit(profile, fs.Args()[1:]); err != nil {
+		log.Fatalf("Failed to %s: %s", name, err)
+	}
+
+	if err := profile.SaveToFile(savePath); err != nil {
+		log.Fatalf("Failed to save player profile: %s", err)
+	}
+}
+
+func playerData(p *vhpackage.PlayerProfile) (*vhpackage.Player, error) {
+	if p.Player == nil {
+		return nil, errors.New("profile has no player data")
+	}
+	return p.Player, nil
+}
+
+// countArg parses the optional count argument following the item name.
+func countArg(args []string, def int) (int, error) {
+	if len(args) < 2 {
+		return def, nil
+	}
+	count, err := strconv.Atoi(args[1])
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid count %q", args[1])
+	}
+	return count, nil
+}
+
+// worldUIDArg returns the world UID given as a number or read from a world
+// metadata file (.fwl).
+func worldUIDArg(arg string) (int64, error) {
+	if strings.HasSuffix(arg, ".fwl") {
+		world, err := vhpackage.NewWorldFromFile(arg, "")
+		if err != nil {
+			return 0, fmt.Errorf("cannot read world metadata: %w", err)
+		}
+		return world.Metadata.UID, nil
+	}
+
+	uid, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid world UID: %w", err)
+	}
+	return uid, nil
+}
+
+func worldPointArgs(args []string) (int64, vhpackage.Vector3, error) {
+	world, err := worldUIDArg(args[0])
+	if err != nil {
+		return 0, vhpackage.Vector3{}, err
+	}
+	pos, err := parseVector3(args[1:4])
+	if err != nil {
+		return 0, vhpackage.Vector3{}, fmt.Errorf("invalid position: %w", err)
+	}
+	return world, pos, nil
+}
+
+func parseVector3(values []string) (vhpackage.Vector3, error) {
+	if len(values) != 3 {
+		return vhpackage.Vector3{}, fmt.Errorf("expected 3 values, got %d", len(values))
+	}
+
+	var f [3]float32
+	for i, s := range values {
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 32)
+		if err != nil {
+			return vhpackage.Vector3{}, err
+		}
+		f[i] = float32(v)
+	}
+	return vhpackage.Vector3{X: f[0], Y: f[1], Z: f[2]}, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	output := fs.String("o", "", "output save file (.fch)")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 || *output == "" {
+		log.Fatalf("usage: %s import profile.json -o save.fch", os.Args[0])
+	}
+
+	profile, err := vhpackage.NewPlayerProfileFromJSONFile(positional[0])
+	if err != nil {
+		log.Fatalf("Failed to import player profile: %s", err)
+	}
+
+	if err := profile.SaveToFile(*output); err != nil {
+		log.Fatalf("Failed to save player profile: %s", err)
+	}
+}
@@ -6,27 +6,82 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 
 	"github.com/Inozuma/vhpackage"
 )
 
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: %s save.fch\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s diff [-json] old.fch new.fch\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s import profile.json -o save.fch\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s migrate -rules rules.json [-dry-run] [-json] profiles_dir\n", os.Args[0])
+
+	names := make([]string, 0, len(editCommands))
+	for name := range editCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s %s %s\n", os.Args[0], name, editCommands[name].usage)
+	}
+	flag.PrintDefaults()
+}
+
 func main() {
+	flag.Usage = usage
 	flag.Parse()
 
 	if flag.NArg() == 0 {
-		log.Fatalf("usage: %s save.fcl", os.Args[0])
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if cmd, ok := editCommands[flag.Arg(0)]; ok {
+		runEdit(flag.Arg(0), cmd, flag.Args()[1:])
+		return
 	}
 
-	savePath := flag.Arg(0)
+	switch flag.Arg(0) {
+	case "diff":
+		runDiff(flag.Args()[1:])
+	case "import":
+		runImport(flag.Args()[1:])
+	case "migrate":
+		runMigrate(flag.Args()[1:])
+	default:
+		runDump(flag.Arg(0))
+	}
+}
 
+func runDump(savePath string) {
 	playerProfile, err := vhpackage.NewPlayerProfileFromFile(savePath)
 	if err != nil {
 		log.Fatalf("Failed to load player save: %s", err)
 	}
 
-	jsondata, err := json.MarshalIndent(playerProfile, "", "  ")
+	printJSON(playerProfile)
+}
+
+// parseArgs parses flags placed before or after positional arguments
+// and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func printJSON(v interface{}) {
+	jsondata, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		log.Fatalf("cannot encode player profile: %s", err)
+		log.Fatalf("cannot encode JSON: %s", err)
 	}
 	fmt.Fprintln(os.Stdout, string(jsondata))
 }
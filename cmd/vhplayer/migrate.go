@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	rulesPath := fs.String("rules", "", "migration rules (JSON)")
+	dryRun := fs.Bool("dry-run", false, "report changes without writing profiles")
+	jsonOutput := fs.Bool("json", false, "output report as JSON")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 || *rulesPath == "" {
+		log.Fatalf("usage: %s migrate -rules rules.json [-dry-run] [-json] profiles_dir", os.Args[0])
+	}
+
+	data, err := ioutil.ReadFile(*rulesPath)
+	if err != nil {
+		log.Fatalf("Failed to read migration rules: %s", err)
+	}
+	rules := &vhpackage.MigrationRules{}
+	if err := json.Unmarshal(data, rules); err != nil {
+		log.Fatalf("Failed to decode migration rules: %s", err)
+	}
+
+	results, err := vhpackage.MigrateProfiles(positional[0], rules, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to migrate profiles: %s", err)
+	}
+
+	if *jsonOutput {
+		printJSON(results)
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(os.Stdout, "%s: error: %s\n", r.File, r.Error)
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "%s: %d changes\n", r.File, len(r.Changes))
+			for _, c := range r.Changes {
+				fmt.Fprintf(os.Stdout, "  %s\n", c)
+			}
+		}
+	}
+
+	for _, r := range results {
+		if r.Error != "" {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runBeds(args []string) {
+	fs := flag.NewFlagSet("beds", flag.ExitOnError)
+	profilesDir := fs.String("profiles", "", "directory of player profiles (.fch)")
+	jsonOutput := fs.Bool("json", false, "output report as JSON")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 2 || *profilesDir == "" {
+		log.Fatalf("usage: %s beds -profiles dir [-json] world.fwl world.db", os.Args[0])
+	}
+
+	profiles, err := vhpackage.NewPlayerProfilesFromDir(*profilesDir)
+	if err != nil {
+		log.Fatalf("Failed to load player profiles: %s", err)
+	}
+
+	world, err := vhpackage.NewWorldFromFile(positional[0], positional[1])
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	audit, err := world.AuditBeds(profiles)
+	if err != nil {
+		log.Fatalf("Failed to audit beds: %s", err)
+	}
+
+	if *jsonOutput {
+		printJSON(audit)
+		return
+	}
+
+	for _, p := range audit.Players {
+		fmt.Fprintf(os.Stdout, "%d %s: %d beds\n", p.ID, p.Name, len(p.Beds))
+		for _, bed := range p.Beds {
+			fmt.Fprintf(os.Stdout, "  bed %s at (%.1f, %.1f, %.1f)\n", bed.UID, bed.Position.X, bed.Position.Y, bed.Position.Z)
+		}
+		switch {
+		case !p.HaveCustomSpawnPoint:
+			fmt.Fprintln(os.Stdout, "  spawns at the world start")
+		case p.MissingSpawnBed():
+			fmt.Fprintf(os.Stdout, "  spawn point (%.1f, %.1f, %.1f) has no bed, spawns at the world start\n",
+				p.SpawnPoint.X, p.SpawnPoint.Y, p.SpawnPoint.Z)
+		default:
+			fmt.Fprintf(os.Stdout, "  spawns at bed %s\n", p.SpawnBed.UID)
+		}
+	}
+
+	for _, bed := range audit.UnknownBeds {
+		fmt.Fprintf(os.Stdout, "unknown player %d %s: bed %s at (%.1f, %.1f, %.1f)\n", bed.Owner, bed.OwnerName,
+			bed.UID, bed.Position.X, bed.Position.Y, bed.Position.Z)
+	}
+}
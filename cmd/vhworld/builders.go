@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runBuilders(args []string) {
+	fs := flag.NewFlagSet("builders", flag.ExitOnError)
+	profilesDir := fs.String("profiles", "", "directory of player profiles (.fch) to resolve names")
+	jsonOutput := fs.Bool("json", false, "output report as JSON")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 {
+		log.Fatalf("usage: %s builders [-profiles dir] [-json] world.db", os.Args[0])
+	}
+
+	var profiles []*vhpackage.PlayerProfile
+	if *profilesDir != "" {
+		var err error
+		profiles, err = vhpackage.NewPlayerProfilesFromDir(*profilesDir)
+		if err != nil {
+			log.Fatalf("Failed to load player profiles: %s", err)
+		}
+	}
+
+	dbPath := positional[0]
+	world, err := vhpackage.NewWorldFromFile("", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		log.Fatalf("Failed to stat world: %s", err)
+	}
+
+	stats := world.BuilderStats(profiles)
+	if *jsonOutput {
+		printJSON(stats)
+		return
+	}
+
+	for _, s := range stats {
+		name := s.Name
+		if name == "" {
+			name = "(unknown)"
+		}
+		lastBuilt := world.RealTime(s.LastTimeCreated, info.ModTime())
+		fmt.Fprintf(os.Stdout, "%d %s: %d pieces in %.0f m² (%.0f,%.0f to %.0f,%.0f), built from day %d to day %d, last around %s\n",
+			s.CreatorID, name, s.Pieces, s.Area(), s.Min.X, s.Min.Y, s.Max.X, s.Max.Y,
+			s.FirstDay, s.LastDay, lastBuilt.Format("2006-01-02 15:04"))
+	}
+}
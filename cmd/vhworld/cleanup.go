@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runCleanup(args []string) {
+	fs := flag.NewFlagSet("cleanup", flag.ExitOnError)
+	by := fs.String("by", "prefab", "summarize issues by prefab or sector")
+	fix := fs.String("fix", "", "comma separated issues to remove: not_persistent, duplicate")
+	jsonOutput := fs.Bool("json", false, "output report as JSON")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 {
+		log.Fatalf("usage: %s cleanup [-by prefab|sector] [-fix issue,...] [-json] world.db", os.Args[0])
+	}
+
+	var group func(vhpackage.ZDOSummary) string
+	switch *by {
+	case "prefab":
+		group = vhpackage.ByPrefab
+	case "sector":
+		group = vhpackage.BySector
+	default:
+		log.Fatalf("unknown summary %q, expected prefab or sector", *by)
+	}
+
+	fixes := make(map[vhpackage.ZDOIssue]bool)
+	for _, f := range strings.Split(*fix, ",") {
+		switch issue := vhpackage.ZDOIssue(f); issue {
+		case "":
+		case vhpackage.IssueNotPersistent, vhpackage.IssueDuplicate:
+			fixes[issue] = true
+		default:
+			log.Fatalf("unknown issue %q", f)
+		}
+	}
+
+	dbPath := positional[0]
+	world, err := vhpackage.NewWorldFromFile("", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	issues := world.FindZDOIssues()
+	summary := vhpackage.SummarizeZDOIssues(issues, group)
+
+	if *jsonOutput {
+		printJSON(summary)
+	} else {
+		keys := make([]string, 0, len(summary))
+		for k := range summary {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s := summary[k]
+			fmt.Fprintf(os.Stdout, "%s %s: %d not persistent, %d duplicate\n", *by, k,
+				s[vhpackage.IssueNotPersistent], s[vhpackage.IssueDuplicate])
+		}
+	}
+
+	if len(fixes) == 0 {
+		return
+	}
+
+	var selected []vhpackage.ZDOIssueEntry
+	for _, i := range issues {
+		if fixes[i.Issue] {
+			selected = append(selected, i)
+		}
+	}
+
+	removed := world.CleanupZDOs(selected)
+	fmt.Fprintf(os.Stderr, "Removed %d ZDOs\n", removed)
+
+	if err := world.SaveToFile("", dbPath); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runCreatures(args []string) {
+	fs := flag.NewFlagSet("creatures", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "output creatures as JSON")
+	minHeight := fs.Float64("min-height", vhpackage.DefaultMinCreatureHeight,
+		"flag creatures below this height as possibly under the terrain")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 {
+		log.Fatalf("usage: %s creatures [-json] [-min-height h] world.db", os.Args[0])
+	}
+
+	world, err := vhpackage.NewWorldFromFile("", positional[0])
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	creatures := world.Creatures(float32(*minHeight))
+	if *jsonOutput {
+		printJSON(creatures)
+		return
+	}
+
+	for _, c := range creatures {
+		name := c.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		prefab := c.PrefabName
+		if prefab == "" {
+			prefab = fmt.Sprint(c.Prefab)
+		}
+		fmt.Fprintf(os.Stdout, "%s %s at (%.1f, %.1f, %.1f), health %.0f", name, prefab,
+			c.Position.X, c.Position.Y, c.Position.Z, c.Health)
+		if c.BelowMinHeight {
+			fmt.Fprint(os.Stdout, " [below min height]")
+		}
+		fmt.Fprintln(os.Stdout)
+	}
+}
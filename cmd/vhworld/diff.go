@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "output changes as JSON")
+	by := fs.String("by", "", "summarize ZDO changes by prefab or sector")
+	positional := parseArgs(fs, args)
+
+	// Worlds are given as .db files, .fwl files or .fwl and .db pairs.
+	var oldMeta, oldDB, newMeta, newDB string
+	switch {
+	case len(positional) == 4:
+		oldMeta, oldDB, newMeta, newDB = positional[0], positional[1], positional[2], positional[3]
+	case len(positional) == 2 && filepath.Ext(positional[0]) == ".fwl" && filepath.Ext(positional[1]) == ".fwl":
+		oldMeta, newMeta = positional[0], positional[1]
+	case len(positional) == 2:
+		oldDB, newDB = positional[0], positional[1]
+	default:
+		log.Fatalf("usage: %s diff [-json] [-by prefab|sector] [old.fwl] old.db [new.fwl] new.db", os.Args[0])
+	}
+
+	var group func(vhpackage.ZDOSummary) string
+	switch *by {
+	case "":
+	case "prefab":
+		group = vhpackage.ByPrefab
+	case "sector":
+		group = vhpackage.BySector
+	default:
+		log.Fatalf("unknown summary %q, expected prefab or sector", *by)
+	}
+
+	var changes []vhpackage.Change
+	if oldMeta != "" {
+		oldWorld, err := vhpackage.NewWorldFromFile(oldMeta, "")
+		if err != nil {
+			log.Fatalf("Failed to load world: %s", err)
+		}
+		newWorld, err := vhpackage.NewWorldFromFile(newMeta, "")
+		if err != nil {
+			log.Fatalf("Failed to load world: %s", err)
+		}
+		changes = vhpackage.DiffWorldMetadata(oldWorld.Metadata, newWorld.Metadata)
+	}
+
+	if oldDB != "" {
+		oldFile, err := os.Open(oldDB)
+		if err != nil {
+			log.Fatalf("Failed to open world: %s", err)
+		}
+		defer oldFile.Close()
+		newFile, err := os.Open(newDB)
+		if err != nil {
+			log.Fatalf("Failed to open world: %s", err)
+		}
+		defer newFile.Close()
+
+		dataChanges, err := vhpackage.DiffWorldData(oldFile, newFile)
+		if err != nil {
+			log.Fatalf("Failed to diff worlds: %s", err)
+		}
+		changes = append(changes, dataChanges...)
+	}
+
+	if group != nil {
+		summary := vhpackage.SummarizeZDOChanges(changes, group)
+		if *jsonOutput {
+			printJSON(summary)
+			return
+		}
+
+		keys := make([]string, 0, len(summary))
+		for k := range summary {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s := summary[k]
+			fmt.Fprintf(os.Stdout, "%s %s: %d added, %d removed, %d moved, %d modified\n", *by, k,
+				s[vhpackage.ChangeAdded], s[vhpackage.ChangeRemoved], s[vhpackage.ChangeMoved], s[vhpackage.ChangeModified])
+		}
+		return
+	}
+
+	if *jsonOutput {
+		if changes == nil {
+			changes = []vhpackage.Change{}
+		}
+		printJSON(changes)
+		return
+	}
+
+	for _, c := range changes {
+		fmt.Fprintln(os.Stdout, c)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runEvents(args []string) {
+	eventsUsage := fmt.Sprintf("usage: %s events status|clear|reset-timer world.db\n       %s events catalog", os.Args[0], os.Args[0])
+	if len(args) == 0 {
+		log.Fatal(eventsUsage)
+	}
+
+	if args[0] == "catalog" {
+		for _, e := range vhpackage.KnownRandomEvents {
+			fmt.Fprintf(os.Stdout, "%-16s %4.0fs %s\n", e.Name, e.Duration, e.Description)
+		}
+		return
+	}
+
+	if len(args) != 2 {
+		log.Fatal(eventsUsage)
+	}
+	dbPath := args[1]
+
+	world, err := vhpackage.NewWorldFromFile("", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	switch args[0] {
+	case "status":
+		if evt := world.ActiveEvent(); evt != nil {
+			fmt.Fprintf(os.Stdout, "Active event: %s", evt.Text)
+			if info, ok := vhpackage.RandomEventDescription(evt.Text); ok {
+				fmt.Fprintf(os.Stdout, " (%s)", info.Description)
+			}
+			fmt.Fprintf(os.Stdout, " at (%.1f, %.1f, %.1f), running for %.0fs",
+				evt.Position.X, evt.Position.Y, evt.Position.Z, evt.Time)
+			if remaining, ok := world.EventRemaining(); ok {
+				fmt.Fprintf(os.Stdout, ", %s left", remaining.Round(time.Second))
+			}
+			fmt.Fprintln(os.Stdout)
+		} else {
+			fmt.Fprintln(os.Stdout, "No active event")
+		}
+		fmt.Fprintf(os.Stdout, "Next event check in %s (%.0f%% chance)\n",
+			world.NextEventCheck().Round(time.Second), vhpackage.EventChance*100)
+		return
+
+	case "clear":
+		if !world.ClearEvent() {
+			log.Printf("World has no active event")
+			return
+		}
+
+	case "reset-timer":
+		world.ResetEventTimer()
+
+	default:
+		log.Fatal(eventsUsage)
+	}
+
+	if err := world.SaveToFile("", dbPath); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+}
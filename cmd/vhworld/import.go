@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	output := fs.String("o", "", "output world path, without extension")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 || *output == "" {
+		log.Fatalf("usage: %s import world.json -o path/to/world", os.Args[0])
+	}
+
+	world, err := vhpackage.NewWorldFromJSONFile(positional[0])
+	if err != nil {
+		log.Fatalf("Failed to import world: %s", err)
+	}
+
+	base := strings.TrimSuffix(*output, filepath.Ext(*output))
+	var metaPath, dbPath string
+	if world.Metadata != nil {
+		metaPath = base + ".fwl"
+	}
+	if world.HasData() {
+		dbPath = base + ".db"
+	}
+
+	if err := world.SaveToFile(metaPath, dbPath); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+}
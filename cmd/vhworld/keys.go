@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runKeys(args []string) {
+	keysUsage := fmt.Sprintf("usage: %s keys list|add|remove world.db [key...]\n       %s keys catalog", os.Args[0], os.Args[0])
+	if len(args) == 0 {
+		log.Fatal(keysUsage)
+	}
+
+	if args[0] == "catalog" {
+		for _, k := range vhpackage.KnownGlobalKeys {
+			fmt.Fprintf(os.Stdout, "%-24s %s\n", k.Name, k.Description)
+		}
+		return
+	}
+
+	if len(args) < 2 {
+		log.Fatal(keysUsage)
+	}
+	dbPath := args[1]
+	keys := args[2:]
+
+	world, err := vhpackage.NewWorldFromFile("", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	switch args[0] {
+	case "list":
+		for _, key := range world.GlobalKeys {
+			desc, _ := vhpackage.GlobalKeyDescription(key)
+			fmt.Fprintf(os.Stdout, "%-24s %s\n", key, desc)
+		}
+		return
+
+	case "add":
+		for _, key := range keys {
+			if !world.AddGlobalKey(key) {
+				log.Printf("World already has key %s", key)
+			}
+		}
+
+	case "remove":
+		for _, key := range keys {
+			if !world.RemoveGlobalKey(key) {
+				log.Printf("World has no key %s", key)
+			}
+		}
+
+	default:
+		log.Fatal(keysUsage)
+	}
+
+	if err := world.SaveToFile("", dbPath); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+}
@@ -10,24 +10,113 @@ import (
 	"github.com/Inozuma/vhpackage"
 )
 
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-prefabs file.json|file.yaml] world_file.fwl [world_file.db]\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s [-prefabs file.json|file.yaml] command ...\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s beds -profiles dir [-json] world.fwl world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s builders [-profiles dir] [-json] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s cleanup [-by prefab|sector] [-fix issue,...] [-json] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s creatures [-json] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s diff [-json] [-by prefab|sector] [old.fwl] old.db [new.fwl] new.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s events status|clear|reset-timer world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s events catalog\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s import world.json -o path/to/world\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s keys list|add|remove world.db [key...]\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s keys catalog\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s meta show world.fwl\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s meta set [-name name] [-new-uid] [-fix-seed] world.fwl\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s purge-dead [-max-age duration] [-inactive] [-dry-run] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s render [-scale n] [-crop minx,minz,maxx,maxz] -o map.png world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s reset-zones [-region x0,y0,x1,y1] [-zones \"x,y x,y\"] [-remove-buildings] [-dry-run] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s signs [-blocklist file [-blank]] [-set uid=text] [-json] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s stats [-top n] [-json] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s terrain [-top n] [-o heights.png [-scale n] [-crop minx,minz,maxx,maxz]] [-json] world.db\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "       %s zones [-json] [-min-distance n] [-heatmap zones.png] [-since old.db] world.db\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
 func main() {
+	prefabs := flag.String("prefabs", "", "JSON or YAML file of additional prefabs, for modded worlds")
+	flag.Usage = usage
 	flag.Parse()
 
+	if *prefabs != "" {
+		if err := vhpackage.Prefabs.LoadFile(*prefabs); err != nil {
+			log.Fatalf("Failed to load prefabs: %s", err)
+		}
+	}
+
 	if flag.NArg() == 0 {
-		log.Fatalf("usage: %s world_file.fwl [world_file.db]", os.Args[0])
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	metaPath := flag.Arg(0)
-	dbPath := flag.Arg(1)
+	switch flag.Arg(0) {
+	case "beds":
+		runBeds(flag.Args()[1:])
+	case "builders":
+		runBuilders(flag.Args()[1:])
+	case "cleanup":
+		runCleanup(flag.Args()[1:])
+	case "creatures":
+		runCreatures(flag.Args()[1:])
+	case "diff":
+		runDiff(flag.Args()[1:])
+	case "events":
+		runEvents(flag.Args()[1:])
+	case "import":
+		runImport(flag.Args()[1:])
+	case "keys":
+		runKeys(flag.Args()[1:])
+	case "meta":
+		runMeta(flag.Args()[1:])
+	case "purge-dead":
+		runPurgeDead(flag.Args()[1:])
+	case "render":
+		runRender(flag.Args()[1:])
+	case "reset-zones":
+		runResetZones(flag.Args()[1:])
+	case "signs":
+		runSigns(flag.Args()[1:])
+	case "stats":
+		runStats(flag.Args()[1:])
+	case "terrain":
+		runTerrain(flag.Args()[1:])
+	case "zones":
+		runZones(flag.Args()[1:])
+	default:
+		runDump(flag.Arg(0), flag.Arg(1))
+	}
+}
 
+func runDump(metaPath, dbPath string) {
 	world, err := vhpackage.NewWorldFromFile(metaPath, dbPath)
 	if err != nil {
 		log.Fatalf("Failed to load world: %s", err)
 	}
 
-	jsondata, err := json.MarshalIndent(world, "", "  ")
+	printJSON(world)
+}
+
+// parseArgs parses flags placed before or after positional arguments
+// and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func printJSON(v interface{}) {
+	jsondata, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		log.Fatalf("cannot encode world: %s", err)
+		log.Fatalf("cannot encode JSON: %s", err)
 	}
 	fmt.Fprintln(os.Stdout, string(jsondata))
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runMeta(args []string) {
+	metaUsage := fmt.Sprintf("usage: %s meta show world.fwl\n       %s meta set [-name name] [-new-uid] [-fix-seed] world.fwl", os.Args[0], os.Args[0])
+	if len(args) == 0 {
+		log.Fatal(metaUsage)
+	}
+
+	switch args[0] {
+	case "show":
+		if len(args) != 2 {
+			log.Fatal(metaUsage)
+		}
+		world, err := vhpackage.NewWorldFromFile(args[1], "")
+		if err != nil {
+			log.Fatalf("Failed to load world: %s", err)
+		}
+		printJSON(world.Metadata)
+		if err := world.Metadata.Validate(); err != nil {
+			log.Printf("Invalid metadata: %s", err)
+		}
+
+	case "set":
+		setMeta(args[1:], metaUsage)
+
+	default:
+		log.Fatal(metaUsage)
+	}
+}
+
+func setMeta(args []string, usage string) {
+	fs := flag.NewFlagSet("meta set", flag.ExitOnError)
+	name := fs.String("name", "", "rename the world")
+	newUID := fs.Bool("new-uid", false, "generate a new world UID, resetting player data of the world")
+	fixSeed := fs.Bool("fix-seed", false, "set the seed from the seed name")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 || (*name == "" && !*newUID && !*fixSeed) {
+		log.Fatal(usage)
+	}
+	metaPath := positional[0]
+
+	world, err := vhpackage.NewWorldFromFile(metaPath, "")
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+	meta := world.Metadata
+
+	if *name != "" {
+		meta.Name = *name
+		base := strings.TrimSuffix(filepath.Base(metaPath), filepath.Ext(metaPath))
+		if base != *name {
+			log.Printf("The game finds worlds by file name, rename the world files to %s.fwl and %s.db", *name, *name)
+		}
+	}
+	if *newUID {
+		if err := meta.NewUID(); err != nil {
+			log.Fatalf("Failed to set world UID: %s", err)
+		}
+	}
+	if *fixSeed && !meta.FixSeed() {
+		log.Printf("World seed already matches seed name")
+	}
+
+	if err := meta.Validate(); err != nil {
+		log.Fatalf("Invalid metadata: %s (use -fix-seed to fix the seed)", err)
+	}
+	if err := world.SaveToFile(metaPath, ""); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+	fmt.Fprintf(os.Stderr, "World %q, seed %q (%d), UID %d\n", meta.Name, meta.SeedName, meta.Seed, meta.UID)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runPurgeDead(args []string) {
+	fs := flag.NewFlagSet("purge-dead", flag.ExitOnError)
+	maxAge := fs.Duration("max-age", 0, "drop dead ZDOs older than this duration of world time (e.g. 168h)")
+	inactive := fs.Bool("inactive", false, "drop dead ZDOs of peers no longer owning any ZDO")
+	dryRun := fs.Bool("dry-run", false, "report changes without writing the world")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 || (*maxAge <= 0 && !*inactive) {
+		log.Fatalf("usage: %s purge-dead [-max-age duration] [-inactive] [-dry-run] world.db", os.Args[0])
+	}
+
+	dbPath := positional[0]
+	world, err := vhpackage.NewWorldFromFile("", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	result := world.CompactDeadZDOs(*maxAge, *inactive)
+	fmt.Fprintf(os.Stdout, "Dead ZDOs: %d -> %d (%d bytes saved)\n", result.Before, result.After, result.SavedBytes)
+
+	if *dryRun || result.Before == result.After {
+		return
+	}
+	if err := world.SaveToFile("", dbPath); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runRender(args []string) {
+	opts := vhpackage.DefaultRenderOptions()
+
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	scale := fs.Float64("scale", float64(opts.Scale), "world units per pixel")
+	crop := fs.String("crop", "", "rendered area as minx,minz,maxx,maxz")
+	output := fs.String("o", "", "output image (.png)")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 || *output == "" {
+		log.Fatalf("usage: %s render [-scale n] [-crop minx,minz,maxx,maxz] -o map.png world.db", os.Args[0])
+	}
+
+	opts.Scale = float32(*scale)
+	if *crop != "" {
+		values, err := parseFloats(*crop, 4)
+		if err != nil {
+			log.Fatalf("Invalid crop area: %s", err)
+		}
+		opts.Min = vhpackage.Vector2{X: values[0], Y: values[1]}
+		opts.Max = vhpackage.Vector2{X: values[2], Y: values[3]}
+	}
+
+	world, err := vhpackage.NewWorldFromFile("", positional[0])
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	img, err := world.Render(opts)
+	if err != nil {
+		log.Fatalf("Failed to render world: %s", err)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		log.Fatalf("Failed to create image: %s", err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		log.Fatalf("Failed to encode image: %s", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("Failed to write image: %s", err)
+	}
+}
+
+// parseFloats parses n comma separated numbers.
+func parseFloats(s string, n int) ([]float32, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("expected %d values, got %d", n, len(parts))
+	}
+
+	values := make([]float32, n)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = float32(v)
+	}
+	return values, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runResetZones(args []string) {
+	fs := flag.NewFlagSet("reset-zones", flag.ExitOnError)
+	region := fs.String("region", "", "zones between two zone coordinates as x0,y0,x1,y1")
+	zoneList := fs.String("zones", "", "zone coordinates as x,y separated by spaces or semicolons")
+	removeBuildings := fs.Bool("remove-buildings", false, "remove player built pieces too")
+	dryRun := fs.Bool("dry-run", false, "report changes without writing the world")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 || (*region == "" && *zoneList == "") {
+		log.Fatalf("usage: %s reset-zones [-region x0,y0,x1,y1] [-zones \"x,y x,y\"] [-remove-buildings] [-dry-run] world.db", os.Args[0])
+	}
+
+	var regions []vhpackage.ZoneRegion
+	if *region != "" {
+		values, err := parseInts(*region, 4)
+		if err != nil {
+			log.Fatalf("Invalid region: %s", err)
+		}
+		r := vhpackage.ZoneRegion{
+			Min: vhpackage.Vector2i{X: values[0], Y: values[1]},
+			Max: vhpackage.Vector2i{X: values[2], Y: values[3]},
+		}
+		if r.Min.X > r.Max.X {
+			r.Min.X, r.Max.X = r.Max.X, r.Min.X
+		}
+		if r.Min.Y > r.Max.Y {
+			r.Min.Y, r.Max.Y = r.Max.Y, r.Min.Y
+		}
+		regions = append(regions, r)
+	}
+	zones := make(map[vhpackage.Vector2i]bool)
+	for _, z := range strings.FieldsFunc(*zoneList, func(r rune) bool { return r == ' ' || r == ';' }) {
+		values, err := parseInts(z, 2)
+		if err != nil {
+			log.Fatalf("Invalid zone %q: %s", z, err)
+		}
+		zones[vhpackage.Vector2i{X: values[0], Y: values[1]}] = true
+	}
+	reset := func(zone vhpackage.Vector2i) bool {
+		if zones[zone] {
+			return true
+		}
+		for _, r := range regions {
+			if r.Contains(zone) {
+				return true
+			}
+		}
+		return false
+	}
+
+	dbPath := positional[0]
+	world, err := vhpackage.NewWorldFromFile("", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	result := world.ResetZones(reset, !*removeBuildings)
+	fmt.Fprintf(os.Stdout, "Reset %d generated zones: %d ZDOs removed, %d player pieces kept, %d locations to regenerate\n",
+		result.Zones, result.RemovedZDOs, result.KeptBuildings, result.ResetLocations)
+
+	if *dryRun {
+		return
+	}
+	if err := world.SaveToFile("", dbPath); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+}
+
+// parseInts parses n comma separated zone coordinates.
+func parseInts(s string, n int) ([]int32, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("expected %d values, got %d", n, len(parts))
+	}
+
+	values := make([]int32, n)
+	for i, p := range parts {
+		v, err := strconv.ParseInt(strings.TrimSpace(p), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = int32(v)
+	}
+	return values, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runSigns(args []string) {
+	fs := flag.NewFlagSet("signs", flag.ExitOnError)
+	blocklistFile := fs.String("blocklist", "", "file of regular expressions, one per line")
+	blank := fs.Bool("blank", false, "blank signs matching the blocklist")
+	set := fs.String("set", "", "replace the text of a sign, as uid=text")
+	jsonOutput := fs.Bool("json", false, "output signs as JSON")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 {
+		log.Fatalf("usage: %s signs [-blocklist file [-blank]] [-set uid=text] [-json] world.db", os.Args[0])
+	}
+	if *blank && *blocklistFile == "" {
+		log.Fatalf("-blank requires -blocklist")
+	}
+
+	dbPath := positional[0]
+	world, err := vhpackage.NewWorldFromFile("", dbPath)
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	if *set != "" {
+		i := strings.Index(*set, "=")
+		if i < 0 {
+			log.Fatalf("invalid -set %q, expected uid=text", *set)
+		}
+		uid, err := vhpackage.ParseZDOID((*set)[:i])
+		if err != nil {
+			log.Fatalf("Invalid sign: %s", err)
+		}
+		if err := world.SetSignText(uid, (*set)[i+1:]); err != nil {
+			log.Fatalf("Failed to edit sign: %s", err)
+		}
+		if err := world.SaveToFile("", dbPath); err != nil {
+			log.Fatalf("Failed to save world: %s", err)
+		}
+		return
+	}
+
+	signs := world.Signs()
+	if *blocklistFile != "" {
+		blocklist, err := vhpackage.NewSignBlocklistFromFile(*blocklistFile)
+		if err != nil {
+			log.Fatalf("Failed to load blocklist: %s", err)
+		}
+		signs = blocklist.Match(signs)
+	}
+
+	if *jsonOutput {
+		printJSON(signs)
+	} else {
+		for _, s := range signs {
+			fmt.Fprintf(os.Stdout, "%s at (%.1f, %.1f, %.1f) by %q: %q", s.UID,
+				s.Position.X, s.Position.Y, s.Position.Z, s.Author, s.Text)
+			if len(s.Matches) > 0 {
+				fmt.Fprintf(os.Stdout, " matches %s", strings.Join(s.Matches, ", "))
+			}
+			fmt.Fprintln(os.Stdout)
+		}
+	}
+
+	if !*blank || len(signs) == 0 {
+		return
+	}
+
+	for _, s := range signs {
+		if err := world.SetSignText(s.UID, ""); err != nil {
+			log.Fatalf("Failed to blank sign: %s", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Blanked %d signs\n", len(signs))
+
+	if err := world.SaveToFile("", dbPath); err != nil {
+		log.Fatalf("Failed to save world: %s", err)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	top := fs.Int("top", 10, "number of sectors and prefabs to report")
+	jsonOutput := fs.Bool("json", false, "output statistics as JSON")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 {
+		log.Fatalf("usage: %s stats [-top n] [-json] world.db", os.Args[0])
+	}
+
+	world, err := vhpackage.NewWorldFromFile("", positional[0])
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	stats := world.Stats(*top)
+	if *jsonOutput {
+		printJSON(stats)
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, "World age: %.1f days (day %d, %s)\n", stats.Days, stats.Day, vhpackage.FormatTimeOfDay(stats.TimeOfDay))
+	fmt.Fprintf(os.Stdout, "ZDOs: %d (%d persistent, %d non persistent)\n", stats.ZDOs, stats.Persistent, stats.NonPersistent)
+	fmt.Fprintf(os.Stdout, "Dead ZDOs: %d\n", stats.DeadZDOs)
+	fmt.Fprintf(os.Stdout, "Generated zones: %d\n", stats.GeneratedZones)
+
+	printCounts("By type", stats.ByType, -1)
+	printCounts("By category", stats.ByCategory, -1)
+	printCounts("Pieces by material", stats.ByMaterial, -1)
+	printCounts("By prefab", stats.ByPrefab, *top)
+
+	fmt.Fprintln(os.Stdout, "Top sectors:")
+	for _, s := range stats.TopSectors {
+		fmt.Fprintf(os.Stdout, "  %d,%d: %d\n", s.Sector.X, s.Sector.Y, s.ZDOs)
+	}
+}
+
+// printCounts prints the n largest counts, or all counts when n is negative.
+func printCounts(title string, counts map[string]int, n int) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if n >= 0 && n < len(keys) {
+		keys = keys[:n]
+	}
+
+	fmt.Fprintf(os.Stdout, "%s:\n", title)
+	for _, k := range keys {
+		fmt.Fprintf(os.Stdout, "  %s: %d\n", k, counts[k])
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+type terrainZone struct {
+	Zone             vhpackage.Vector2i `json:"zone"`
+	Operations       int                `json:"operations"`
+	ModifiedVertices int                `json:"modified_vertices"`
+	PaintedVertices  int                `json:"painted_vertices"`
+}
+
+func runTerrain(args []string) {
+	opts := vhpackage.DefaultRenderOptions()
+
+	fs := flag.NewFlagSet("terrain", flag.ExitOnError)
+	top := fs.Int("top", 0, "only report the n most modified zones")
+	scale := fs.Float64("scale", float64(opts.Scale), "world units per pixel of the image")
+	crop := fs.String("crop", "", "area of the image as minx,minz,maxx,maxz")
+	output := fs.String("o", "", "write an image of height changes (.png)")
+	jsonOutput := fs.Bool("json", false, "output report as JSON")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 {
+		log.Fatalf("usage: %s terrain [-top n] [-o heights.png [-scale n] [-crop minx,minz,maxx,maxz]] [-json] world.db", os.Args[0])
+	}
+
+	opts.Scale = float32(*scale)
+	if *crop != "" {
+		values, err := parseFloats(*crop, 4)
+		if err != nil {
+			log.Fatalf("Invalid crop area: %s", err)
+		}
+		opts.Min = vhpackage.Vector2{X: values[0], Y: values[1]}
+		opts.Max = vhpackage.Vector2{X: values[2], Y: values[3]}
+	}
+
+	world, err := vhpackage.NewWorldFromFile("", positional[0])
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	comps, err := world.TerrainComps()
+	if err != nil {
+		log.Fatalf("Failed to decode terrain: %s", err)
+	}
+
+	if *output != "" {
+		img, err := vhpackage.RenderTerrain(comps, opts)
+		if err != nil {
+			log.Fatalf("Failed to render terrain: %s", err)
+		}
+
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create image: %s", err)
+		}
+		if err := png.Encode(f, img); err != nil {
+			f.Close()
+			log.Fatalf("Failed to encode image: %s", err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatalf("Failed to write image: %s", err)
+		}
+	}
+
+	if *top > 0 && *top < len(comps) {
+		comps = comps[:*top]
+	}
+
+	zones := make([]terrainZone, len(comps))
+	for i, t := range comps {
+		zones[i] = terrainZone{
+			Zone:             t.Zone,
+			Operations:       t.Operations,
+			ModifiedVertices: t.ModifiedVertices(),
+			PaintedVertices:  t.PaintedVertices(),
+		}
+	}
+
+	if *jsonOutput {
+		printJSON(zones)
+		return
+	}
+
+	for _, z := range zones {
+		fmt.Fprintf(os.Stdout, "zone %d,%d: %d modified vertices, %d painted vertices, %d operations\n",
+			z.Zone.X, z.Zone.Y, z.ModifiedVertices, z.PaintedVertices, z.Operations)
+	}
+}
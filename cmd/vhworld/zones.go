@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+
+	"github.com/Inozuma/vhpackage"
+)
+
+func runZones(args []string) {
+	opts := vhpackage.DefaultRenderOptions()
+
+	fs := flag.NewFlagSet("zones", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "output report as JSON")
+	minDistance := fs.Int("min-distance", 10, "minimum distance in zones to player buildings for outlying zones")
+	heatmap := fs.String("heatmap", "", "write a heatmap of generated zones (.png)")
+	scale := fs.Float64("scale", float64(opts.Scale), "heatmap world units per pixel")
+	since := fs.String("since", "", "older world (.db) to report zone growth from")
+	positional := parseArgs(fs, args)
+
+	if len(positional) != 1 {
+		log.Fatalf("usage: %s zones [-json] [-min-distance n] [-heatmap zones.png] [-since old.db] world.db", os.Args[0])
+	}
+
+	world, err := vhpackage.NewWorldFromFile("", positional[0])
+	if err != nil {
+		log.Fatalf("Failed to load world: %s", err)
+	}
+
+	report := struct {
+		*vhpackage.ZoneReport
+		Added   []vhpackage.Vector2i `json:"added,omitempty"`
+		Removed []vhpackage.Vector2i `json:"removed,omitempty"`
+	}{
+		ZoneReport: world.ZoneReport(*minDistance),
+	}
+
+	if *since != "" {
+		old, err := vhpackage.NewWorldFromFile("", *since)
+		if err != nil {
+			log.Fatalf("Failed to load world: %s", err)
+		}
+		report.Added, report.Removed = vhpackage.ZoneGrowth(old, world)
+	}
+
+	if *heatmap != "" {
+		opts.Scale = float32(*scale)
+		img, err := world.RenderZoneHeatmap(opts)
+		if err != nil {
+			log.Fatalf("Failed to render heatmap: %s", err)
+		}
+		f, err := os.Create(*heatmap)
+		if err != nil {
+			log.Fatalf("Failed to create heatmap: %s", err)
+		}
+		if err := png.Encode(f, img); err != nil {
+			f.Close()
+			log.Fatalf("Failed to encode heatmap: %s", err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatalf("Failed to write heatmap: %s", err)
+		}
+	}
+
+	if *jsonOutput {
+		printJSON(report)
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, "Generated zones: %d (%.2f km²)\n", report.GeneratedZones, report.Area)
+	fmt.Fprintf(os.Stdout, "Zones with buildings: %d\n", report.BuiltZones)
+	if *since != "" {
+		fmt.Fprintf(os.Stdout, "Zone growth: %d added, %d removed\n", len(report.Added), len(report.Removed))
+	}
+	fmt.Fprintf(os.Stdout, "Outlying zones (>= %d zones from buildings): %d\n", *minDistance, len(report.Outlying))
+	for _, z := range report.Outlying {
+		fmt.Fprintf(os.Stdout, "  %d,%d: %d\n", z.Zone.X, z.Zone.Y, z.Distance)
+	}
+}
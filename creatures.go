@@ -0,0 +1,56 @@
+package vhpackage
+
+import "sort"
+
+// Creature is a tamed creature of the world.
+type Creature struct {
+	UID        ZDOID   `json:"uid"`
+	Name       string  `json:"name"`
+	Prefab     int     `json:"prefab"`
+	PrefabName string  `json:"prefab_name"`
+	Position   Vector3 `json:"position"`
+	// Health is zero when the creature has never been hurt.
+	Health float32 `json:"health"`
+	// Peer is the network peer simulating the creature, not the player who
+	// tamed it: the game does not record the taming player.
+	Peer int64 `json:"peer"`
+	// BelowMinHeight is set when the creature is lower than the height given
+	// to Creatures, usually after falling through the ground.
+	BelowMinHeight bool `json:"below_min_height"`
+}
+
+// DefaultMinCreatureHeight is a heuristic threshold under which creatures
+// are likely to have fallen through the ground. It is a little below the
+// usual ocean floor and does not account for the actual terrain height.
+const DefaultMinCreatureHeight = -10
+
+var (
+	tamedHash     = StableHashCode("tamed")
+	tamedNameHash = StableHashCode("TamedName")
+	healthHash    = StableHashCode("health")
+)
+
+// Creatures returns the tamed creatures of the world, sorted by name.
+// Creatures lower than minHeight are flagged with BelowMinHeight.
+func (w *World) Creatures(minHeight float32) []*Creature {
+	creatures := []*Creature{}
+	for _, zdo := range w.ZDOs {
+		if !zdo.GetBoolHash(tamedHash, false) {
+			continue
+		}
+
+		creatures = append(creatures, &Creature{
+			UID:            zdo.UID,
+			Name:           zdo.GetStringHash(tamedNameHash, ""),
+			Prefab:         zdo.Prefab,
+			PrefabName:     Prefabs.Name(zdo.Prefab),
+			Position:       zdo.Position,
+			Health:         zdo.GetFloatHash(healthHash, 0),
+			Peer:           zdo.Owner,
+			BelowMinHeight: zdo.Position.Y < minHeight,
+		})
+	}
+
+	sort.SliceStable(creatures, func(i, j int) bool { return creatures[i].Name < creatures[j].Name })
+	return creatures
+}
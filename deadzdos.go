@@ -0,0 +1,56 @@
+package vhpackage
+
+import "time"
+
+// deadZDOSize is the size of a dead ZDO entry in the world data: its
+// ZDOID and time.
+const deadZDOSize = 8 + 4 + 8
+
+// DeadZDOCompaction reports the result of a dead ZDO compaction.
+type DeadZDOCompaction struct {
+	Before int `json:"before"`
+	After  int `json:"after"`
+	// SavedBytes is the size removed from the world data.
+	SavedBytes int `json:"saved_bytes"`
+}
+
+// DeadZDOAge returns how long before the world network time the ZDO died.
+func (w *World) DeadZDOAge(zid ZDOID) (time.Duration, bool) {
+	ticks, ok := w.DeadZDOs[zid]
+	if !ok {
+		return 0, false
+	}
+	return w.Age(ticks), true
+}
+
+// CompactDeadZDOs drops dead ZDO entries older than maxAge, when maxAge is
+// positive. With dropInactive, entries created by peers that no longer own
+// or created any ZDO of the world are dropped too.
+func (w *World) CompactDeadZDOs(maxAge time.Duration, dropInactive bool) DeadZDOCompaction {
+	result := DeadZDOCompaction{Before: len(w.DeadZDOs)}
+
+	var active map[int64]bool
+	if dropInactive {
+		active = make(map[int64]bool)
+		for _, zdo := range w.ZDOs {
+			active[zdo.UID.UserID] = true
+			active[zdo.Owner] = true
+		}
+	}
+
+	for zid := range w.DeadZDOs {
+		if maxAge > 0 {
+			if age, _ := w.DeadZDOAge(zid); age > maxAge {
+				delete(w.DeadZDOs, zid)
+				continue
+			}
+		}
+		if dropInactive && !active[zid.UserID] {
+			delete(w.DeadZDOs, zid)
+		}
+	}
+
+	result.After = len(w.DeadZDOs)
+	result.SavedBytes = (result.Before - result.After) * deadZDOSize
+	return result
+}
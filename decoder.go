@@ -0,0 +1,98 @@
+package vhpackage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WorldDecoder reads a world database (.db) one ZDO at a time.
+// It allows processing large worlds without holding every ZDO in memory.
+type WorldDecoder struct {
+	pkg   *ZPackage
+	world *World
+
+	zdoCount int
+	zdoRead  int
+}
+
+// NewWorldDecoder reads the world header from r and returns a decoder
+// positioned on the first ZDO.
+func NewWorldDecoder(r io.Reader) (*WorldDecoder, error) {
+	return newWorldDecoder(NewZPackageReader(bufio.NewReader(r)), &World{})
+}
+
+func newWorldDecoder(pkg *ZPackage, w *World) (*WorldDecoder, error) {
+	d := &WorldDecoder{
+		pkg:   pkg,
+		world: w,
+	}
+
+	if err := w.readHeader(pkg); err != nil {
+		return nil, err
+	}
+
+	var err error
+	d.zdoCount, err = w.readZDOManHeader(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read ZDOMan section: %w", err)
+	}
+
+	return d, nil
+}
+
+// World returns the world being decoded, without its ZDOs.
+// Sections stored after the ZDOs are only available once Next returned io.EOF.
+func (d *WorldDecoder) World() *World {
+	return d.world
+}
+
+// Count returns the number of ZDOs stored in the world.
+func (d *WorldDecoder) Count() int {
+	return d.zdoCount
+}
+
+// Next returns the next ZDO. After the last ZDO, it reads the remaining
+// sections of the world and returns io.EOF.
+func (d *WorldDecoder) Next() (*ZDO, error) {
+	if d.zdoRead == d.zdoCount {
+		if err := d.readTrailer(); err != nil {
+			return nil, err
+		}
+		// Mark trailer as read.
+		d.zdoRead++
+		return nil, io.EOF
+	}
+	if d.zdoRead > d.zdoCount {
+		return nil, io.EOF
+	}
+
+	zdo, err := d.world.readZDO(d.pkg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read ZDOMan section: (ZDO #%d) %w", d.zdoRead, err)
+	}
+	d.zdoRead++
+
+	return zdo, nil
+}
+
+func (d *WorldDecoder) readTrailer() error {
+	w := d.world
+
+	// Dead ZDOs
+	if err := w.readDeadZDOs(d.pkg); err != nil {
+		return fmt.Errorf("cannot read ZDOMan section: %w", err)
+	}
+
+	// ZoneSystem
+	if err := w.readZoneSystem(d.pkg); err != nil {
+		return fmt.Errorf("cannot read ZoneSystem section: %w", err)
+	}
+
+	// RandEventSystem
+	if err := w.readRandEventSystem(d.pkg); err != nil {
+		return fmt.Errorf("cannot read RandEventSystem section: %w", err)
+	}
+
+	return nil
+}
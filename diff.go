@@ -0,0 +1,282 @@
+package vhpackage
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind describes how a value changed between two saves.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change represents a single difference between two saves.
+// Path identifies the changed value with "/" separated field names,
+// following the structure of the JSON output.
+type Change struct {
+	Kind ChangeKind  `json:"kind"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+
+	// ZDO describes the ZDO affected by a world change.
+	ZDO *ZDOSummary `json:"zdo,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %v", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %v", c.Path, c.Old)
+	case ChangeMoved:
+		return fmt.Sprintf("> %s: %v -> %v", c.Path, c.Old, c.New)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", c.Path, c.Old, c.New)
+	}
+}
+
+// DiffProfiles returns the changes needed to go from profile a to profile b.
+func DiffProfiles(a, b *PlayerProfile) []Change {
+	d := &differ{}
+
+	d.value("Name", a.Name, b.Name)
+	d.value("ID", a.ID, b.ID)
+	d.value("StartSeed", a.StartSeed, b.StartSeed)
+
+	d.value("Stats/Kills", a.Stats.Kills, b.Stats.Kills)
+	d.value("Stats/Deaths", a.Stats.Deaths, b.Stats.Deaths)
+	d.value("Stats/Crafts", a.Stats.Crafts, b.Stats.Crafts)
+	d.value("Stats/Builds", a.Stats.Builds, b.Stats.Builds)
+
+	d.worldData(a.WorldData, b.WorldData)
+	d.player(a.Player, b.Player)
+
+	return d.changes
+}
+
+// differ accumulates changes while walking two values.
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, path string, old, new interface{}) {
+	d.changes = append(d.changes, Change{Kind: kind, Path: path, Old: old, New: new})
+}
+
+// value records a modification when old and new differ.
+// Both values must be comparable.
+func (d *differ) value(path string, old, new interface{}) {
+	if old != new {
+		d.add(ChangeModified, path, old, new)
+	}
+}
+
+// set records elements added to or removed from a list of names.
+func (d *differ) set(path string, old, new []string) {
+	oldSet := make(map[string]bool, len(old))
+	for _, s := range old {
+		oldSet[s] = true
+	}
+	newSet := make(map[string]bool, len(new))
+	for _, s := range new {
+		newSet[s] = true
+	}
+
+	for _, s := range sortedKeys(oldSet) {
+		if !newSet[s] {
+			d.add(ChangeRemoved, path, s, nil)
+		}
+	}
+	for _, s := range sortedKeys(newSet) {
+		if !oldSet[s] {
+			d.add(ChangeAdded, path, nil, s)
+		}
+	}
+}
+
+// counts records changes between two maps of named quantities.
+func (d *differ) counts(path string, old, new map[string]int) {
+	keys := make(map[string]bool, len(old)+len(new))
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+
+	for _, k := range sortedKeys(keys) {
+		o, inOld := old[k]
+		n, inNew := new[k]
+		switch {
+		case !inOld:
+			d.add(ChangeAdded, path+"/"+k, nil, n)
+		case !inNew:
+			d.add(ChangeRemoved, path+"/"+k, o, nil)
+		case o != n:
+			d.add(ChangeModified, path+"/"+k, o, n)
+		}
+	}
+}
+
+func (d *differ) worldData(old, new map[int64]WorldPlayerData) {
+	keys := make(map[int64]bool, len(old)+len(new))
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	uids := make([]int64, 0, len(keys))
+	for k := range keys {
+		uids = append(uids, k)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	for _, uid := range uids {
+		path := "WorldData/" + strconv.FormatInt(uid, 10)
+		o, inOld := old[uid]
+		n, inNew := new[uid]
+		switch {
+		case !inOld:
+			d.add(ChangeAdded, path, nil, uid)
+			continue
+		case !inNew:
+			d.add(ChangeRemoved, path, uid, nil)
+			continue
+		}
+
+		d.value(path+"/HaveCustomSpawnPoint", o.HaveCustomSpawnPoint, n.HaveCustomSpawnPoint)
+		d.value(path+"/SpawnPoint", o.SpawnPoint, n.SpawnPoint)
+		d.value(path+"/HaveLogoutPoint", o.HaveLogoutPoint, n.HaveLogoutPoint)
+		d.value(path+"/LogoutPoint", o.LogoutPoint, n.LogoutPoint)
+		d.value(path+"/HaveDeathPoint", o.HaveDeathPoint, n.HaveDeathPoint)
+		d.value(path+"/DeathPoint", o.DeathPoint, n.DeathPoint)
+		d.value(path+"/HomePoint", o.HomePoint, n.HomePoint)
+		d.pins(path+"/Map/Pins", o.Map, n.Map)
+	}
+}
+
+func (d *differ) pins(path string, old, new *Map) {
+	var oldPins, newPins []Pin
+	if old != nil {
+		oldPins = old.Pins
+	}
+	if new != nil {
+		newPins = new.Pins
+	}
+
+	inOld := make(map[Pin]bool, len(oldPins))
+	for _, pin := range oldPins {
+		inOld[pin] = true
+	}
+	inNew := make(map[Pin]bool, len(newPins))
+	for _, pin := range newPins {
+		inNew[pin] = true
+	}
+
+	for _, pin := range oldPins {
+		if !inNew[pin] {
+			d.add(ChangeRemoved, path, pin, nil)
+		}
+	}
+	for _, pin := range newPins {
+		if !inOld[pin] {
+			d.add(ChangeAdded, path, nil, pin)
+		}
+	}
+}
+
+func (d *differ) player(old, new *Player) {
+	if old == nil {
+		old = &Player{}
+	}
+	if new == nil {
+		new = &Player{}
+	}
+
+	d.value("Player/MaxHealth", old.MaxHealth, new.MaxHealth)
+	d.value("Player/GuardianPower", old.GuardianPower, new.GuardianPower)
+	d.value("Player/Beard", old.Beard, new.Beard)
+	d.value("Player/Hair", old.Hair, new.Hair)
+	d.value("Player/SkinColor", old.SkinColor, new.SkinColor)
+	d.value("Player/HairColor", old.HairColor, new.HairColor)
+	d.value("Player/PlayerModel", old.PlayerModel, new.PlayerModel)
+
+	d.counts("Player/Inventory", inventoryCounts(old.Inventory), inventoryCounts(new.Inventory))
+	d.skills("Player/Skills", old.Skills, new.Skills)
+	d.counts("Player/KnownStations", old.KnownStations, new.KnownStations)
+
+	d.set("Player/KnownRecipes", old.KnownRecipes, new.KnownRecipes)
+	d.set("Player/KnownMaterial", old.KnownMaterial, new.KnownMaterial)
+	d.set("Player/Uniques", old.Uniques, new.Uniques)
+	d.set("Player/Trophies", old.Trophies, new.Trophies)
+	d.set("Player/KnownBiomes", intsToStrings(old.KnownBiomes), intsToStrings(new.KnownBiomes))
+}
+
+func (d *differ) skills(path string, old, new []*Skill) {
+	oldLevels := make(map[SkillType]float32, len(old))
+	for _, s := range old {
+		oldLevels[s.Type] = s.Level
+	}
+	newLevels := make(map[SkillType]float32, len(new))
+	for _, s := range new {
+		newLevels[s.Type] = s.Level
+	}
+
+	types := make([]SkillType, 0, len(oldLevels)+len(newLevels))
+	for t := range oldLevels {
+		types = append(types, t)
+	}
+	for t := range newLevels {
+		if _, ok := oldLevels[t]; !ok {
+			types = append(types, t)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	for _, t := range types {
+		o, inOld := oldLevels[t]
+		n, inNew := newLevels[t]
+		p := path + "/" + t.String()
+		switch {
+		case !inOld:
+			d.add(ChangeAdded, p, nil, n)
+		case !inNew:
+			d.add(ChangeRemoved, p, o, nil)
+		case o != n:
+			d.add(ChangeModified, p, o, n)
+		}
+	}
+}
+
+// inventoryCounts sums item stacks by item name.
+func inventoryCounts(items []*Item) map[string]int {
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[item.Name] += item.Stack
+	}
+	return counts
+}
+
+func intsToStrings(l []int) []string {
+	s := make([]string, len(l))
+	for i, v := range l {
+		s[i] = strconv.Itoa(v)
+	}
+	return s
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
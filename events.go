@@ -0,0 +1,95 @@
+package vhpackage
+
+import "time"
+
+// RandomEventInfo describes a known random event.
+type RandomEventInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Duration is the length of the event, in seconds.
+	Duration float32 `json:"duration"`
+}
+
+// KnownRandomEvents lists random events (raids) of the game.
+var KnownRandomEvents = []RandomEventInfo{
+	{"army_eikthyr", "Eikthyr rallies the creatures of the forest", 90},
+	{"army_theelder", "The forest is moving...", 120},
+	{"army_bonemass", "A foul smell from the swamp", 150},
+	{"army_moder", "A cold wind blows from the mountains", 150},
+	{"army_goblin", "The horde is attacking", 120},
+	{"army_gjall", "What's that sound?", 120},
+	{"army_seekers", "They sought you out", 120},
+	{"foresttrolls", "The ground is shaking", 80},
+	{"skeletons", "Skeleton surprise", 120},
+	{"blobs", "A foul smell from the swamp", 120},
+	{"wolves", "You are being hunted", 120},
+	{"bats", "You stirred the cauldron", 120},
+	{"surtlings", "There's a smell of sulfur in the air", 120},
+}
+
+// RandomEventDescription returns the description of a known random event.
+func RandomEventDescription(name string) (RandomEventInfo, bool) {
+	for _, e := range KnownRandomEvents {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return RandomEventInfo{}, false
+}
+
+// EventInterval is the time between two random event checks. Each check
+// starts an event with a probability of EventChance.
+const (
+	EventInterval = 46 * time.Minute
+	EventChance   = 0.2
+)
+
+// ActiveEvent returns the random event running in the world, or nil.
+func (w *World) ActiveEvent() *RandomEvent {
+	if w.Event == nil || w.Event.Text == "" {
+		return nil
+	}
+	return w.Event
+}
+
+// EventRemaining returns the time left before the active event ends. It
+// returns false when no event is running or the event is unknown.
+func (w *World) EventRemaining() (time.Duration, bool) {
+	evt := w.ActiveEvent()
+	if evt == nil {
+		return 0, false
+	}
+	info, ok := RandomEventDescription(evt.Text)
+	if !ok {
+		return 0, false
+	}
+	remaining := NetTimeDuration(float64(info.Duration - evt.Time))
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// NextEventCheck returns the time left before the next random event check.
+func (w *World) NextEventCheck() time.Duration {
+	next := EventInterval - NetTimeDuration(float64(w.EventTimer))
+	if next < 0 {
+		return 0
+	}
+	return next
+}
+
+// ClearEvent stops the active random event.
+// It returns false if no event was running.
+func (w *World) ClearEvent() bool {
+	if w.ActiveEvent() == nil {
+		return false
+	}
+	w.Event = &RandomEvent{}
+	return true
+}
+
+// ResetEventTimer restarts the interval before the next random event check.
+func (w *World) ResetEventTimer() {
+	w.EventTimer = 0
+}
@@ -0,0 +1,61 @@
+package vhpackage
+
+import (
+	"fmt"
+	"time"
+)
+
+// DayLength is the length of an in-game day, in seconds (EnvMan.m_dayLengthSec).
+const DayLength = 1200
+
+// ZDO creation and dead ZDO times are stored as .NET ticks of the world
+// network time.
+const ticksPerSecond = 10000000
+
+// TicksDuration converts ticks of network time to the time elapsed since the
+// world creation.
+func TicksDuration(ticks int64) time.Duration {
+	return time.Duration(ticks) * (time.Second / ticksPerSecond)
+}
+
+// NetTimeDuration converts a network time, in seconds, to the time elapsed
+// since the world creation.
+func NetTimeDuration(netTime float64) time.Duration {
+	return time.Duration(netTime * float64(time.Second))
+}
+
+// GameDay returns the in-game day at a time since the world creation.
+func GameDay(t time.Duration) int {
+	return int(t / (DayLength * time.Second))
+}
+
+// TimeOfDay returns the fraction of the in-game day elapsed at a time since
+// the world creation, from 0 at midnight to 1.
+func TimeOfDay(t time.Duration) float64 {
+	day := DayLength * time.Second
+	return float64(t%day) / float64(day)
+}
+
+// FormatTimeOfDay formats a fraction of day as an in-game clock time.
+func FormatTimeOfDay(fraction float64) string {
+	minutes := int(fraction * 24 * 60)
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Uptime returns the time elapsed in the world since its creation.
+func (w *World) Uptime() time.Duration {
+	return NetTimeDuration(w.NetTime)
+}
+
+// Age returns how long before the world network time the given time, in
+// ticks, happened.
+func (w *World) Age(ticks int64) time.Duration {
+	return w.Uptime() - TicksDuration(ticks)
+}
+
+// RealTime returns the real time at which the given time, in ticks,
+// happened, for a world saved at savedAt. It assumes the world ran
+// continuously.
+func (w *World) RealTime(ticks int64, savedAt time.Time) time.Time {
+	return savedAt.Add(-w.Age(ticks))
+}
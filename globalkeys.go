@@ -0,0 +1,91 @@
+package vhpackage
+
+import "strings"
+
+// GlobalKey describes a known global key.
+type GlobalKey struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// KnownGlobalKeys lists global keys set by the game, for boss kills and
+// progression, and world modifiers.
+var KnownGlobalKeys = []GlobalKey{
+	// Progression
+	{"defeated_eikthyr", "Eikthyr has been defeated"},
+	{"defeated_gdking", "The Elder has been defeated"},
+	{"defeated_bonemass", "Bonemass has been defeated"},
+	{"defeated_dragon", "Moder has been defeated"},
+	{"defeated_goblinking", "Yagluth has been defeated"},
+	{"defeated_queen", "The Queen has been defeated"},
+	{"KilledTroll", "A troll has been killed"},
+	{"killed_surtling", "A surtling has been killed"},
+	{"KilledBat", "A bat has been killed"},
+
+	// World modifiers
+	{"nomap", "Map is disabled"},
+	{"noportals", "Portals are disabled"},
+	{"nobossportals", "Portals are disabled near boss altars"},
+	{"nobuildcost", "Building costs no resources"},
+	{"nocraftcost", "Crafting costs no resources"},
+	{"noworkbench", "Building does not require a workbench"},
+	{"allpiecesunlocked", "All building pieces are unlocked"},
+	{"allrecipesunlocked", "All recipes are unlocked"},
+	{"passivemobs", "Creatures do not attack unless provoked"},
+	{"playerevents", "Raids are based on each player progression"},
+	{"teleportall", "Every item can be teleported"},
+	{"fire", "Fire spreads"},
+	{"dungeonbuild", "Building is allowed in dungeons"},
+	{"deathkeepequip", "Equipped items are kept on death"},
+	{"deathdeleteall", "Every item is lost on death"},
+	{"deathdeleteunequipped", "Unequipped items are lost on death"},
+	{"deathskillsreset", "Skills are reset on death"},
+}
+
+// GlobalKeyDescription returns the description of a known global key.
+func GlobalKeyDescription(key string) (string, bool) {
+	for _, k := range KnownGlobalKeys {
+		if strings.EqualFold(k.Name, key) {
+			return k.Description, true
+		}
+	}
+	return "", false
+}
+
+// HasGlobalKey reports whether the world has the global key, ignoring case.
+func (w *World) HasGlobalKey(key string) bool {
+	return w.globalKeyIndex(key) >= 0
+}
+
+// AddGlobalKey sets a global key.
+// It returns false if the world already had the key.
+func (w *World) AddGlobalKey(key string) bool {
+	if w.HasGlobalKey(key) {
+		return false
+	}
+	w.GlobalKeys = append(w.GlobalKeys, key)
+	return true
+}
+
+// RemoveGlobalKey removes every occurrence of a global key, ignoring case.
+// It returns false if the world did not have the key.
+func (w *World) RemoveGlobalKey(key string) bool {
+	keys := w.GlobalKeys[:0]
+	for _, k := range w.GlobalKeys {
+		if !strings.EqualFold(k, key) {
+			keys = append(keys, k)
+		}
+	}
+	removed := len(keys) != len(w.GlobalKeys)
+	w.GlobalKeys = keys
+	return removed
+}
+
+func (w *World) globalKeyIndex(key string) int {
+	for i, k := range w.GlobalKeys {
+		if strings.EqualFold(k, key) {
+			return i
+		}
+	}
+	return -1
+}
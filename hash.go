@@ -0,0 +1,21 @@
+package vhpackage
+
+import "unicode/utf16"
+
+// StableHashCode returns the hash of s as computed by the game
+// (Utils.GetStableHashCode). Prefabs and ZDO property names are stored
+// with this hash.
+func StableHashCode(s string) int {
+	chars := utf16.Encode([]rune(s))
+
+	var num, num2 int32 = 5381, 5381
+	for i := 0; i < len(chars) && chars[i] != 0; i += 2 {
+		num = ((num << 5) + num) ^ int32(chars[i])
+		if i == len(chars)-1 || chars[i+1] == 0 {
+			break
+		}
+		num2 = ((num2 << 5) + num2) ^ int32(chars[i+1])
+	}
+
+	return int(num + num2*1566083941)
+}
@@ -0,0 +1,47 @@
+package vhpackage
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+)
+
+// Validate checks that the metadata can be written and loaded by the game.
+// The seed must be the stable hash of the seed name, which is how the game
+// derives it.
+func (m *WorldMetadata) Validate() error {
+	if m.Version <= 0 || m.Version > WorldVersion {
+		return fmt.Errorf("unsupported world metadata version %d", m.Version)
+	}
+	if m.Name == "" {
+		return fmt.Errorf("world has no name")
+	}
+	if seed := StableHashCode(m.SeedName); m.Seed != seed {
+		return fmt.Errorf("seed %d does not match seed name %q (%d)", m.Seed, m.SeedName, seed)
+	}
+	return nil
+}
+
+// FixSeed sets the seed from the seed name.
+// It returns false if the seed was already consistent.
+func (m *WorldMetadata) FixSeed() bool {
+	seed := StableHashCode(m.SeedName)
+	if m.Seed == seed {
+		return false
+	}
+	m.Seed = seed
+	return true
+}
+
+// NewUID gives the world a new random UID. Player profiles store their
+// world data by world UID, so players lose their map, spawn point and
+// death markers in a world with a new UID.
+func (m *WorldMetadata) NewUID() error {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Errorf("cannot generate world UID: %w", err)
+	}
+	// Keep UIDs positive, like the game.
+	m.UID = int64(binary.LittleEndian.Uint64(b[:]) >> 1)
+	return nil
+}
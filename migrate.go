@@ -0,0 +1,124 @@
+package vhpackage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+// MigrationRules describes changes applied to every profile of a server.
+type MigrationRules struct {
+	// WorldUIDs moves player world data from a world UID to another.
+	WorldUIDs map[int64]int64 `json:"world_uids"`
+	// ClearDeathPoints removes the death point of every world.
+	ClearDeathPoints bool `json:"clear_death_points"`
+	// StripItems removes items with these names from the inventory.
+	StripItems []string `json:"strip_items"`
+	// MaxSkillLevel caps skill levels when set.
+	MaxSkillLevel *float32 `json:"max_skill_level,omitempty"`
+}
+
+// Apply applies the rules to a profile.
+func (r *MigrationRules) Apply(p *PlayerProfile) error {
+	froms := make([]int64, 0, len(r.WorldUIDs))
+	for from := range r.WorldUIDs {
+		froms = append(froms, from)
+	}
+	sort.Slice(froms, func(i, j int) bool { return froms[i] < froms[j] })
+	for _, from := range froms {
+		if _, ok := p.WorldData[from]; !ok {
+			continue
+		}
+		if err := p.MoveWorldData(from, r.WorldUIDs[from], false); err != nil {
+			return err
+		}
+	}
+
+	if r.ClearDeathPoints {
+		for key, wpd := range p.WorldData {
+			wpd.HaveDeathPoint = false
+			wpd.DeathPoint = Vector3{}
+			p.WorldData[key] = wpd
+		}
+	}
+
+	if p.Player == nil {
+		return nil
+	}
+
+	for _, name := range r.StripItems {
+		p.Player.RemoveItem(name, 0)
+	}
+
+	if r.MaxSkillLevel != nil {
+		max := *r.MaxSkillLevel
+		for _, skill := range p.Player.Skills {
+			if skill.Level > max {
+				skill.Level = max
+				skill.Accumulator = 0
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProfileMigration is the result of migrating a profile file.
+type ProfileMigration struct {
+	File    string   `json:"file"`
+	Changes []Change `json:"changes"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// MigrateProfiles applies the rules to every profile (.fch) in dir and
+// reports the changes made to each of them. Modified profiles are
+// replaced atomically, keeping the previous file as a .bak backup.
+// With dryRun, no file is written.
+func MigrateProfiles(dir string, rules *MigrationRules, dryRun bool) ([]ProfileMigration, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.fch"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	results := make([]ProfileMigration, 0, len(files))
+	for _, file := range files {
+		result := ProfileMigration{File: file}
+		result.Changes, err = migrateProfile(file, rules, dryRun)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func migrateProfile(file string, rules *MigrationRules, dryRun bool) ([]Change, error) {
+	filedata, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the profile twice to keep an untouched copy for the report.
+	original, err := NewPlayerProfileFromData(filedata)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load profile: %w", err)
+	}
+	profile, err := NewPlayerProfileFromData(filedata)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load profile: %w", err)
+	}
+
+	if err := rules.Apply(profile); err != nil {
+		return nil, err
+	}
+
+	changes := DiffProfiles(original, profile)
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+
+	return changes, profile.SaveToFile(file)
+}
@@ -1,9 +1,12 @@
 package vhpackage
 
 import (
+	"bytes"
+	"crypto/sha512"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 )
 
 type PlayerProfile struct {
@@ -59,6 +62,7 @@ type Player struct {
 	TimeSinceDeath        float32
 	GuardianPower         string
 	GuardianPowerCooldown float32
+	InventoryVersion      int
 	Inventory             []*Item
 	KnownRecipes          []string
 	KnownStations         map[string]int
@@ -74,6 +78,7 @@ type Player struct {
 	HairColor             Vector3
 	PlayerModel           int
 	Foods                 []*Food
+	SkillsVersion         int
 	Skills                []*Skill
 }
 
@@ -96,7 +101,7 @@ type Food struct {
 }
 
 type Skill struct {
-	Type        int // TODO: enum for skill type
+	Type        SkillType
 	Level       float32
 	Accumulator float32
 }
@@ -358,7 +363,7 @@ func readPlayerData(pkg *ZPackage) (*Player, error) {
 	}
 
 	// inventory
-	p.Inventory, err = readInventory(pkg)
+	p.InventoryVersion, p.Inventory, err = readInventory(pkg)
 	if err != nil {
 		return nil, fmt.Errorf("cannot read player inventory: %w", err)
 	}
@@ -515,7 +520,7 @@ func readPlayerData(pkg *ZPackage) (*Player, error) {
 
 	// skills
 	if p.Version >= 17 {
-		p.Skills, err = readSkills(pkg)
+		p.SkillsVersion, p.Skills, err = readSkills(pkg)
 		if err != nil {
 			return nil, err
 		}
@@ -524,27 +529,27 @@ func readPlayerData(pkg *ZPackage) (*Player, error) {
 	return p, nil
 }
 
-func readInventory(pkg *ZPackage) ([]*Item, error) {
+func readInventory(pkg *ZPackage) (int, []*Item, error) {
 	version, err := pkg.ReadInt()
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 	count, err := pkg.ReadInt()
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
 	inventory := make([]*Item, count)
 	for i := 0; i < count; i++ {
 		item, err := readInventoryItem(pkg, version)
 		if err != nil {
-			return nil, err
+			return 0, nil, err
 		}
 
 		inventory[i] = item
 	}
 
-	return inventory, nil
+	return version, inventory, nil
 }
 
 func readInventoryItem(pkg *ZPackage, version int) (*Item, error) {
@@ -583,38 +588,530 @@ func readFood(pkg *ZPackage, version int) (*Food, error) {
 	return food, nil
 }
 
-func readSkills(pkg *ZPackage) ([]*Skill, error) {
+func readSkills(pkg *ZPackage) (int, []*Skill, error) {
 	version, err := pkg.ReadInt()
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
 	count, err := pkg.ReadInt()
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
 	skills := make([]*Skill, count)
 	for i := 0; i < count; i++ {
 		skill := &Skill{}
 
-		skill.Type, err = pkg.ReadInt()
+		skillType, err := pkg.ReadInt()
 		if err != nil {
-			return nil, err
+			return 0, nil, err
 		}
+		skill.Type = SkillType(skillType)
 		skill.Level, err = pkg.ReadSingle()
 		if err != nil {
-			return nil, err
+			return 0, nil, err
 		}
 		if version >= 2 {
 			skill.Accumulator, err = pkg.ReadSingle()
 			if err != nil {
-				return nil, err
+				return 0, nil, err
 			}
 		}
 
 		skills[i] = skill
 	}
 
-	return skills, nil
+	return version, skills, nil
+}
+
+func (pin Pin) String() string {
+	return fmt.Sprintf("%q at (%.1f, %.1f, %.1f)", pin.Name, pin.Position.X, pin.Position.Y, pin.Position.Z)
+}
+
+// Data encodes the player profile in the save file format, followed by
+// the SHA512 hash of the profile.
+func (p *PlayerProfile) Data() ([]byte, error) {
+	profile := &bytes.Buffer{}
+	if err := p.writePlayerProfile(NewZPackageWriter(profile)); err != nil {
+		return nil, err
+	}
+	hash := sha512.Sum512(profile.Bytes())
+
+	buf := &bytes.Buffer{}
+	pkg := NewZPackageWriter(buf)
+	if err := pkg.WriteByteArray(profile.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := pkg.WriteByteArray(hash[:]); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SaveToFile writes the player profile to file.
+// An existing file is kept as a backup with the .bak extension.
+func (p *PlayerProfile) SaveToFile(file string) error {
+	data, err := p.Data()
+	if err != nil {
+		return err
+	}
+
+	return writeFile(file, data)
+}
+
+func (p *PlayerProfile) writePlayerProfile(pkg *ZPackage) error {
+	if err := pkg.WriteInt(p.Version); err != nil {
+		return fmt.Errorf("cannot write player version: %w", err)
+	}
+
+	// Player stats
+	if p.Version >= 28 {
+		for _, stat := range []int{p.Stats.Kills, p.Stats.Deaths, p.Stats.Crafts, p.Stats.Builds} {
+			if err := pkg.WriteInt(stat); err != nil {
+				return fmt.Errorf("cannot write player stats: %w", err)
+			}
+		}
+	}
+
+	// World player data
+	if err := pkg.WriteInt(len(p.WorldData)); err != nil {
+		return fmt.Errorf("cannot write player world data count: %w", err)
+	}
+
+	keys := make([]int64, 0, len(p.WorldData))
+	for key := range p.WorldData {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, key := range keys {
+		if err := writeWorldPlayerData(pkg, p.Version, key, p.WorldData[key]); err != nil {
+			return fmt.Errorf("cannot write world player data %d: %w", key, err)
+		}
+	}
+
+	// Player info
+	if err := pkg.WriteString(p.Name); err != nil {
+		return err
+	}
+	if err := pkg.WriteLong(p.ID); err != nil {
+		return err
+	}
+	if err := pkg.WriteString(p.StartSeed); err != nil {
+		return err
+	}
+	if err := pkg.WriteBool(p.Player != nil); err != nil {
+		return err
+	}
+	if p.Player != nil {
+		data, err := p.Player.Data()
+		if err != nil {
+			return fmt.Errorf("cannot write player data: %w", err)
+		}
+		if err := pkg.WriteByteArray(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeWorldPlayerData(pkg *ZPackage, version int, key int64, wpd WorldPlayerData) error {
+	if err := pkg.WriteLong(key); err != nil {
+		return err
+	}
+	if err := pkg.WriteBool(wpd.HaveCustomSpawnPoint); err != nil {
+		return err
+	}
+	if err := pkg.WriteVector3(wpd.SpawnPoint); err != nil {
+		return err
+	}
+	if err := pkg.WriteBool(wpd.HaveLogoutPoint); err != nil {
+		return err
+	}
+	if err := pkg.WriteVector3(wpd.LogoutPoint); err != nil {
+		return err
+	}
+
+	if version >= 30 {
+		if err := pkg.WriteBool(wpd.HaveDeathPoint); err != nil {
+			return err
+		}
+		if err := pkg.WriteVector3(wpd.DeathPoint); err != nil {
+			return err
+		}
+	}
+
+	if err := pkg.WriteVector3(wpd.HomePoint); err != nil {
+		return err
+	}
+
+	if version >= 29 {
+		if err := pkg.WriteBool(wpd.Map != nil); err != nil {
+			return err
+		}
+		if wpd.Map != nil {
+			data, err := wpd.Map.Data()
+			if err != nil {
+				return err
+			}
+			if err := pkg.WriteByteArray(data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Data encodes the map data.
+func (m *Map) Data() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	pkg := NewZPackageWriter(buf)
+
+	if err := pkg.WriteInt(m.Version); err != nil {
+		return nil, err
+	}
+	if err := pkg.WriteInt(m.TextureSize); err != nil {
+		return nil, err
+	}
+	if len(m.Explored) != m.TextureSize*m.TextureSize {
+		return nil, fmt.Errorf("explored map has %d values, expected %d", len(m.Explored), m.TextureSize*m.TextureSize)
+	}
+	if err := pkg.write(m.Explored); err != nil {
+		return nil, err
+	}
+
+	// pins
+	if m.Version >= 2 {
+		if err := pkg.WriteInt(len(m.Pins)); err != nil {
+			return nil, err
+		}
+		for _, pin := range m.Pins {
+			if err := writePin(pkg, pin); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// public pos ref
+	if m.Version >= 4 {
+		if err := pkg.WriteBool(m.PublicReferencePosition); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writePin(pkg *ZPackage, pin Pin) error {
+	if err := pkg.WriteString(pin.Name); err != nil {
+		return err
+	}
+	if err := pkg.WriteVector3(pin.Position); err != nil {
+		return err
+	}
+	if err := pkg.WriteInt(pin.Type); err != nil {
+		return err
+	}
+	return pkg.WriteBool(pin.IsChecked)
+}
+
+// Data encodes the player data.
+// Sections skipped when reading old versions are written empty.
+func (p *Player) Data() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := p.writePlayerData(NewZPackageWriter(buf)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (p *Player) writePlayerData(pkg *ZPackage) error {
+	if err := pkg.WriteInt(p.Version); err != nil {
+		return err
+	}
+
+	if p.Version >= 7 {
+		if err := pkg.WriteSingle(p.MaxHealth); err != nil {
+			return err
+		}
+	}
+	if err := pkg.WriteSingle(p.Health); err != nil {
+		return err
+	}
+
+	if p.Version >= 10 {
+		if err := pkg.WriteSingle(p.Stamina); err != nil {
+			return err
+		}
+	}
+	if p.Version >= 8 {
+		if err := pkg.WriteBool(p.FirstSpawn); err != nil {
+			return err
+		}
+	}
+	if p.Version >= 20 {
+		if err := pkg.WriteSingle(p.TimeSinceDeath); err != nil {
+			return err
+		}
+	}
+	if p.Version >= 23 {
+		if err := pkg.WriteString(p.GuardianPower); err != nil {
+			return err
+		}
+	}
+	if p.Version >= 24 {
+		if err := pkg.WriteSingle(p.GuardianPowerCooldown); err != nil {
+			return err
+		}
+	}
+	if p.Version == 2 {
+		if err := pkg.WriteZDOID(ZDOID{}); err != nil {
+			return err
+		}
+	}
+
+	// inventory
+	if err := writeInventory(pkg, p.InventoryVersion, p.Inventory); err != nil {
+		return fmt.Errorf("cannot write player inventory: %w", err)
+	}
+
+	// known recipes
+	if err := pkg.WriteList(p.KnownRecipes); err != nil {
+		return err
+	}
+
+	// known stations
+	if p.Version < 15 {
+		if err := pkg.WriteList([]string{}); err != nil {
+			return err
+		}
+	} else {
+		if err := pkg.WriteInt(len(p.KnownStations)); err != nil {
+			return err
+		}
+		for _, name := range sortedStringKeys(p.KnownStations) {
+			if err := pkg.WriteString(name); err != nil {
+				return err
+			}
+			if err := pkg.WriteInt(p.KnownStations[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// known material
+	if err := pkg.WriteList(p.KnownMaterial); err != nil {
+		return err
+	}
+
+	// shown tutorials
+	if p.Version < 19 || p.Version >= 21 {
+		if err := pkg.WriteList(p.ShownTutorials); err != nil {
+			return err
+		}
+	}
+
+	// uniques
+	if p.Version >= 6 {
+		if err := pkg.WriteList(p.Uniques); err != nil {
+			return err
+		}
+	}
+
+	// trophies
+	if p.Version >= 9 {
+		if err := pkg.WriteList(p.Trophies); err != nil {
+			return err
+		}
+	}
+
+	// known biomes
+	if p.Version >= 18 {
+		if err := pkg.WriteList(p.KnownBiomes); err != nil {
+			return err
+		}
+	}
+
+	// known texts
+	if p.Version >= 22 {
+		if err := pkg.WriteInt(len(p.KnownTexts)); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(p.KnownTexts))
+		for key := range p.KnownTexts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := pkg.WriteString(key); err != nil {
+				return err
+			}
+			if err := pkg.WriteString(p.KnownTexts[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// beard and hair
+	if p.Version >= 4 {
+		if err := pkg.WriteString(p.Beard); err != nil {
+			return err
+		}
+		if err := pkg.WriteString(p.Hair); err != nil {
+			return err
+		}
+	}
+
+	// skin and hair color
+	if p.Version >= 5 {
+		if err := pkg.WriteVector3(p.SkinColor); err != nil {
+			return err
+		}
+		if err := pkg.WriteVector3(p.HairColor); err != nil {
+			return err
+		}
+	}
+
+	// player model
+	if p.Version >= 11 {
+		if err := pkg.WriteInt(p.PlayerModel); err != nil {
+			return err
+		}
+	}
+
+	// food consumed
+	if p.Version >= 12 {
+		foods := p.Foods
+		if p.Version < 14 {
+			// old version, food is not decoded
+			foods = nil
+		}
+		if err := pkg.WriteInt(len(foods)); err != nil {
+			return err
+		}
+		for _, food := range foods {
+			if err := writeFood(pkg, p.Version, food); err != nil {
+				return err
+			}
+		}
+	}
+
+	// skills
+	if p.Version >= 17 {
+		if err := writeSkills(pkg, p.SkillsVersion, p.Skills); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeInventory(pkg *ZPackage, version int, inventory []*Item) error {
+	if err := pkg.WriteInt(version); err != nil {
+		return err
+	}
+	if err := pkg.WriteInt(len(inventory)); err != nil {
+		return err
+	}
+
+	for _, item := range inventory {
+		if err := writeInventoryItem(pkg, version, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeInventoryItem(pkg *ZPackage, version int, item *Item) error {
+	if err := pkg.WriteString(item.Name); err != nil {
+		return err
+	}
+	if err := pkg.WriteInt(item.Stack); err != nil {
+		return err
+	}
+	if err := pkg.WriteSingle(item.Durability); err != nil {
+		return err
+	}
+	if err := pkg.WriteVector2i(item.Position); err != nil {
+		return err
+	}
+	if err := pkg.WriteBool(item.Equiped); err != nil {
+		return err
+	}
+	if version >= 101 {
+		if err := pkg.WriteInt(item.Quality); err != nil {
+			return err
+		}
+	}
+	if version >= 102 {
+		if err := pkg.WriteInt(item.Variant); err != nil {
+			return err
+		}
+	}
+	if version >= 103 {
+		if err := pkg.WriteLong(item.CrafterID); err != nil {
+			return err
+		}
+		if err := pkg.WriteString(item.CrafterName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeFood(pkg *ZPackage, version int, food *Food) error {
+	if err := pkg.WriteString(food.Name); err != nil {
+		return err
+	}
+	if err := pkg.WriteSingle(food.Health); err != nil {
+		return err
+	}
+
+	if version >= 16 {
+		if err := pkg.WriteSingle(food.Stamina); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeSkills(pkg *ZPackage, version int, skills []*Skill) error {
+	if err := pkg.WriteInt(version); err != nil {
+		return err
+	}
+	if err := pkg.WriteInt(len(skills)); err != nil {
+		return err
+	}
+
+	for _, skill := range skills {
+		if err := pkg.WriteInt(int(skill.Type)); err != nil {
+			return err
+		}
+		if err := pkg.WriteSingle(skill.Level); err != nil {
+			return err
+		}
+		if version >= 2 {
+			if err := pkg.WriteSingle(skill.Accumulator); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func sortedStringKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,175 @@
+package vhpackage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Player inventory grid size.
+const (
+	InventoryWidth  = 8
+	InventoryHeight = 4
+)
+
+// ParseSkillType returns the skill type matching name, ignoring case.
+func ParseSkillType(name string) (SkillType, error) {
+	for t, n := range skillNames {
+		if strings.EqualFold(n, name) {
+			return t, nil
+		}
+	}
+	return SkillNone, fmt.Errorf("unknown skill %q", name)
+}
+
+// SetSkillLevel sets the level of a skill, adding the skill if the player
+// never trained it. Level must be between 0 and 100.
+func (p *Player) SetSkillLevel(t SkillType, level float32) error {
+	if level < 0 || level > 100 {
+		return fmt.Errorf("skill level %v out of range [0, 100]", level)
+	}
+
+	for _, skill := range p.Skills {
+		if skill.Type == t {
+			skill.Level = level
+			skill.Accumulator = 0
+			return nil
+		}
+	}
+
+	p.Skills = append(p.Skills, &Skill{Type: t, Level: level})
+	return nil
+}
+
+// AddItem puts item in the first free slot of the inventory.
+func (p *Player) AddItem(item *Item) error {
+	used := make(map[Vector2i]bool, len(p.Inventory))
+	for _, it := range p.Inventory {
+		used[it.Position] = true
+	}
+
+	for y := int32(0); y < InventoryHeight; y++ {
+		for x := int32(0); x < InventoryWidth; x++ {
+			pos := Vector2i{X: x, Y: y}
+			if !used[pos] {
+				item.Position = pos
+				p.Inventory = append(p.Inventory, item)
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("inventory is full")
+}
+
+// RemoveItem removes up to amount items named name from the inventory,
+// emptying stacks in inventory order. An amount of 0 removes every item
+// with that name. It returns the number of items removed.
+func (p *Player) RemoveItem(name string, amount int) int {
+	removed := 0
+	inventory := p.Inventory[:0]
+	for _, item := range p.Inventory {
+		if item.Name != name || (amount > 0 && removed >= amount) {
+			inventory = append(inventory, item)
+			continue
+		}
+
+		if amount > 0 && item.Stack > amount-removed {
+			item.Stack -= amount - removed
+			removed = amount
+			inventory = append(inventory, item)
+			continue
+		}
+
+		removed += item.Stack
+	}
+	p.Inventory = inventory
+
+	return removed
+}
+
+// AddKnownRecipe adds a recipe to the known recipes.
+// It returns false if the recipe was already known.
+func (p *Player) AddKnownRecipe(name string) bool {
+	return addUnique(&p.KnownRecipes, name)
+}
+
+// AddKnownMaterial adds a material to the known materials.
+// It returns false if the material was already known.
+func (p *Player) AddKnownMaterial(name string) bool {
+	return addUnique(&p.KnownMaterial, name)
+}
+
+// ResetGuardianPowerCooldown makes the guardian power available immediately.
+func (p *Player) ResetGuardianPowerCooldown() {
+	p.GuardianPowerCooldown = 0
+}
+
+// SetSpawnPoint sets a custom spawn point for the world with the given UID.
+func (p *PlayerProfile) SetSpawnPoint(world int64, pos Vector3) {
+	wpd := p.WorldData[world]
+	wpd.SpawnPoint = pos
+	wpd.HaveCustomSpawnPoint = true
+	p.setWorldData(world, wpd)
+}
+
+// SetHomePoint sets the home point for the world with the given UID.
+func (p *PlayerProfile) SetHomePoint(world int64, pos Vector3) {
+	wpd := p.WorldData[world]
+	wpd.HomePoint = pos
+	p.setWorldData(world, wpd)
+}
+
+func (p *PlayerProfile) setWorldData(world int64, wpd WorldPlayerData) {
+	if p.WorldData == nil {
+		p.WorldData = make(map[int64]WorldPlayerData)
+	}
+	p.WorldData[world] = wpd
+}
+
+func addUnique(l *[]string, s string) bool {
+	for _, v := range *l {
+		if v == s {
+			return false
+		}
+	}
+	*l = append(*l, s)
+	return true
+}
+
+// MoveWorldData moves the player data of world from to world to, for
+// example after a world has been regenerated with a new UID.
+// Existing data for world to is only replaced if overwrite is true.
+func (p *PlayerProfile) MoveWorldData(from, to int64, overwrite bool) error {
+	if err := p.CopyWorldData(from, to, overwrite); err != nil {
+		return err
+	}
+	if from != to {
+		delete(p.WorldData, from)
+	}
+	return nil
+}
+
+// CopyWorldData copies the player data of world from to world to.
+// Existing data for world to is only replaced if overwrite is true.
+func (p *PlayerProfile) CopyWorldData(from, to int64, overwrite bool) error {
+	wpd, ok := p.WorldData[from]
+	if !ok {
+		return fmt.Errorf("no player data for world %d", from)
+	}
+	if _, ok := p.WorldData[to]; ok && !overwrite && from != to {
+		return fmt.Errorf("player data already exists for world %d", to)
+	}
+
+	if wpd.Map != nil {
+		wpd.Map = wpd.Map.clone()
+	}
+	p.WorldData[to] = wpd
+	return nil
+}
+
+func (m *Map) clone() *Map {
+	c := *m
+	c.Explored = append([]bool(nil), m.Explored...)
+	c.Pins = append([]Pin(nil), m.Pins...)
+	return &c
+}
@@ -0,0 +1,121 @@
+package vhpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Latest versions of the player save formats known by this package.
+const (
+	PlayerProfileVersion = 33
+	PlayerVersion        = 24
+	InventoryVersion     = 103
+	SkillsVersion        = 2
+	MapVersion           = 4
+)
+
+// NewPlayerProfileFromJSONFile reads a player profile from a JSON file
+// as produced by encoding a PlayerProfile.
+func NewPlayerProfileFromJSONFile(file string) (*PlayerProfile, error) {
+	filedata, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPlayerProfileFromJSON(filedata)
+}
+
+// NewPlayerProfileFromJSON decodes a player profile from JSON.
+// Unknown fields are rejected and the profile is validated so that it can
+// be written back in the save file format.
+func NewPlayerProfileFromJSON(data []byte) (*PlayerProfile, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	p := &PlayerProfile{}
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("cannot decode player profile: %w", err)
+	}
+
+	return p, p.Validate()
+}
+
+// Validate checks that the profile can be written in the save file format.
+func (p *PlayerProfile) Validate() error {
+	if p.Version <= 0 || p.Version > PlayerProfileVersion {
+		return fmt.Errorf("unsupported player profile version %d", p.Version)
+	}
+	if p.Name == "" {
+		return fmt.Errorf("player name is empty")
+	}
+
+	for key, wpd := range p.WorldData {
+		if wpd.Map == nil {
+			continue
+		}
+		if err := wpd.Map.Validate(); err != nil {
+			return fmt.Errorf("invalid map for world %d: %w", key, err)
+		}
+	}
+
+	if p.Player != nil {
+		if err := p.Player.Validate(); err != nil {
+			return fmt.Errorf("invalid player data: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the map data can be written.
+func (m *Map) Validate() error {
+	if m.Version <= 0 || m.Version > MapVersion {
+		return fmt.Errorf("unsupported map version %d", m.Version)
+	}
+	if m.TextureSize < 0 || len(m.Explored) != m.TextureSize*m.TextureSize {
+		return fmt.Errorf("explored map has %d values, expected %d", len(m.Explored), m.TextureSize*m.TextureSize)
+	}
+	return nil
+}
+
+// Validate checks that the player data can be written.
+func (p *Player) Validate() error {
+	if p.Version <= 0 || p.Version > PlayerVersion {
+		return fmt.Errorf("unsupported player version %d", p.Version)
+	}
+	if p.InventoryVersion < 100 || p.InventoryVersion > InventoryVersion {
+		return fmt.Errorf("unsupported inventory version %d", p.InventoryVersion)
+	}
+	if p.Version >= 17 && (p.SkillsVersion <= 0 || p.SkillsVersion > SkillsVersion) {
+		return fmt.Errorf("unsupported skills version %d", p.SkillsVersion)
+	}
+
+	for i, item := range p.Inventory {
+		if item == nil || item.Name == "" {
+			return fmt.Errorf("inventory item #%d has no name", i)
+		}
+		if item.Stack <= 0 {
+			return fmt.Errorf("inventory item %s has invalid stack %d", item.Name, item.Stack)
+		}
+		if item.Position.X < 0 || item.Position.X >= InventoryWidth || item.Position.Y < 0 || item.Position.Y >= InventoryHeight {
+			return fmt.Errorf("inventory item %s is outside the inventory grid at %v", item.Name, item.Position)
+		}
+	}
+	for i, food := range p.Foods {
+		if food == nil {
+			return fmt.Errorf("food #%d is empty", i)
+		}
+	}
+	for i, skill := range p.Skills {
+		if skill == nil {
+			return fmt.Errorf("skill #%d is empty", i)
+		}
+		if skill.Level < 0 || skill.Level > 100 {
+			return fmt.Errorf("skill %s has invalid level %v", skill.Type, skill.Level)
+		}
+	}
+
+	return nil
+}
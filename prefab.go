@@ -0,0 +1,322 @@
+package vhpackage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrefabCategory classifies ZDOs by the kind of object they represent.
+type PrefabCategory string
+
+const (
+	CategoryUnknown    PrefabCategory = ""
+	CategoryBuilding   PrefabCategory = "building"
+	CategoryContainer  PrefabCategory = "container"
+	CategoryPortal     PrefabCategory = "portal"
+	CategoryBed        PrefabCategory = "bed"
+	CategoryTombstone  PrefabCategory = "tombstone"
+	CategorySign       PrefabCategory = "sign"
+	CategoryCreature   PrefabCategory = "creature"
+	CategoryItem       PrefabCategory = "item"
+	CategoryVegetation PrefabCategory = "vegetation"
+	CategoryLocation   PrefabCategory = "location"
+)
+
+// IsPiece reports whether the category is a piece built by players.
+func (c PrefabCategory) IsPiece() bool {
+	switch c {
+	case CategoryBuilding, CategoryContainer, CategoryPortal, CategoryBed, CategoryTombstone, CategorySign:
+		return true
+	}
+	return false
+}
+
+// PrefabInfo describes a prefab.
+type PrefabInfo struct {
+	Name     string         `json:"name" yaml:"name"`
+	Category PrefabCategory `json:"category" yaml:"category"`
+	// Biome is where the prefab is found or first built.
+	Biome string `json:"biome,omitempty" yaml:"biome,omitempty"`
+	// Material is the building material of pieces.
+	Material string `json:"material,omitempty" yaml:"material,omitempty"`
+}
+
+// KnownPrefabs lists prefabs of the game.
+var KnownPrefabs = []PrefabInfo{
+	// Notable pieces
+	{"portal_wood", CategoryPortal, "BlackForest", "wood"},
+	{"portal", CategoryPortal, "Mountain", "stone"},
+	{"bed", CategoryBed, "Meadows", "wood"},
+	{"piece_bed02", CategoryBed, "BlackForest", "wood"},
+	{"Player_tombstone", CategoryTombstone, "", ""},
+	{"sign", CategorySign, "Meadows", "wood"},
+	{"piece_chest_wood", CategoryContainer, "Meadows", "wood"},
+	{"piece_chest", CategoryContainer, "BlackForest", "iron"},
+	{"piece_chest_private", CategoryContainer, "Swamp", "iron"},
+	{"piece_chest_blackmetal", CategoryContainer, "Plains", "blackmetal"},
+
+	// Wood pieces
+	{"wood_floor", CategoryBuilding, "Meadows", "wood"},
+	{"wood_floor_1x1", CategoryBuilding, "Meadows", "wood"},
+	{"woodwall", CategoryBuilding, "Meadows", "wood"},
+	{"wood_wall_half", CategoryBuilding, "Meadows", "wood"},
+	{"wood_wall_quarter", CategoryBuilding, "Meadows", "wood"},
+	{"wood_wall_roof", CategoryBuilding, "Meadows", "wood"},
+	{"wood_wall_roof_upsidedown", CategoryBuilding, "Meadows", "wood"},
+	{"wood_beam", CategoryBuilding, "Meadows", "wood"},
+	{"wood_beam_1", CategoryBuilding, "Meadows", "wood"},
+	{"wood_beam_26", CategoryBuilding, "Meadows", "wood"},
+	{"wood_beam_45", CategoryBuilding, "Meadows", "wood"},
+	{"wood_pole", CategoryBuilding, "Meadows", "wood"},
+	{"wood_pole2", CategoryBuilding, "Meadows", "wood"},
+	{"wood_stair", CategoryBuilding, "Meadows", "wood"},
+	{"wood_stepladder", CategoryBuilding, "Meadows", "wood"},
+	{"wood_door", CategoryBuilding, "Meadows", "wood"},
+	{"wood_gate", CategoryBuilding, "Meadows", "wood"},
+	{"wood_fence", CategoryBuilding, "Meadows", "wood"},
+	{"wood_window", CategoryBuilding, "Meadows", "wood"},
+	{"wood_roof", CategoryBuilding, "Meadows", "wood"},
+	{"wood_roof_45", CategoryBuilding, "Meadows", "wood"},
+	{"wood_roof_top", CategoryBuilding, "Meadows", "wood"},
+	{"wood_roof_top_45", CategoryBuilding, "Meadows", "wood"},
+	{"wood_roof_ocorner", CategoryBuilding, "Meadows", "wood"},
+	{"wood_roof_icorner", CategoryBuilding, "Meadows", "wood"},
+	{"wood_wall_log", CategoryBuilding, "BlackForest", "corewood"},
+	{"wood_wall_log_4x0.5", CategoryBuilding, "BlackForest", "corewood"},
+	{"wood_log_26", CategoryBuilding, "BlackForest", "corewood"},
+	{"wood_log_45", CategoryBuilding, "BlackForest", "corewood"},
+	{"wood_pole_log", CategoryBuilding, "BlackForest", "corewood"},
+	{"wood_pole_log_4", CategoryBuilding, "BlackForest", "corewood"},
+
+	// Stone pieces
+	{"stone_wall_1x1", CategoryBuilding, "BlackForest", "stone"},
+	{"stone_wall_2x1", CategoryBuilding, "BlackForest", "stone"},
+	{"stone_wall_4x2", CategoryBuilding, "BlackForest", "stone"},
+	{"stone_floor_2x2", CategoryBuilding, "BlackForest", "stone"},
+	{"stone_arch", CategoryBuilding, "BlackForest", "stone"},
+	{"stone_pillar", CategoryBuilding, "BlackForest", "stone"},
+	{"stone_stair", CategoryBuilding, "BlackForest", "stone"},
+
+	// Iron pieces
+	{"iron_grate", CategoryBuilding, "Swamp", "iron"},
+	{"iron_floor_1x1", CategoryBuilding, "Swamp", "iron"},
+	{"iron_floor_2x2", CategoryBuilding, "Swamp", "iron"},
+	{"iron_wall_1x1", CategoryBuilding, "Swamp", "iron"},
+	{"iron_wall_2x2", CategoryBuilding, "Swamp", "iron"},
+	{"woodiron_beam", CategoryBuilding, "Swamp", "iron"},
+	{"woodiron_pole", CategoryBuilding, "Swamp", "iron"},
+
+	// Crafting stations and furniture
+	{"piece_workbench", CategoryBuilding, "Meadows", "wood"},
+	{"piece_cookingstation", CategoryBuilding, "Meadows", "wood"},
+	{"fire_pit", CategoryBuilding, "Meadows", "stone"},
+	{"bonfire", CategoryBuilding, "Meadows", "stone"},
+	{"hearth", CategoryBuilding, "BlackForest", "stone"},
+	{"piece_groundtorch_wood", CategoryBuilding, "Meadows", "wood"},
+	{"piece_groundtorch", CategoryBuilding, "BlackForest", "iron"},
+	{"piece_walltorch", CategoryBuilding, "BlackForest", "iron"},
+	{"piece_sharpstakes", CategoryBuilding, "Meadows", "wood"},
+	{"forge", CategoryBuilding, "BlackForest", "stone"},
+	{"smelter", CategoryBuilding, "BlackForest", "stone"},
+	{"charcoal_kiln", CategoryBuilding, "BlackForest", "stone"},
+	{"piece_cauldron", CategoryBuilding, "Swamp", "iron"},
+	{"piece_stonecutter", CategoryBuilding, "Mountain", "stone"},
+	{"piece_artisanstation", CategoryBuilding, "Plains", "wood"},
+	{"blast_furnace", CategoryBuilding, "Plains", "stone"},
+
+	// Creatures
+	{"Boar", CategoryCreature, "Meadows", ""},
+	{"Deer", CategoryCreature, "Meadows", ""},
+	{"Neck", CategoryCreature, "Meadows", ""},
+	{"Greyling", CategoryCreature, "Meadows", ""},
+	{"Eikthyr", CategoryCreature, "Meadows", ""},
+	{"Crow", CategoryCreature, "Meadows", ""},
+	{"Seagal", CategoryCreature, "Ocean", ""},
+	{"Greydwarf", CategoryCreature, "BlackForest", ""},
+	{"Greydwarf_Elite", CategoryCreature, "BlackForest", ""},
+	{"Greydwarf_Shaman", CategoryCreature, "BlackForest", ""},
+	{"Troll", CategoryCreature, "BlackForest", ""},
+	{"Skeleton", CategoryCreature, "BlackForest", ""},
+	{"Ghost", CategoryCreature, "BlackForest", ""},
+	{"gd_king", CategoryCreature, "BlackForest", ""},
+	{"Draugr", CategoryCreature, "Swamp", ""},
+	{"Draugr_Elite", CategoryCreature, "Swamp", ""},
+	{"Blob", CategoryCreature, "Swamp", ""},
+	{"BlobElite", CategoryCreature, "Swamp", ""},
+	{"Leech", CategoryCreature, "Swamp", ""},
+	{"Surtling", CategoryCreature, "Swamp", ""},
+	{"Wraith", CategoryCreature, "Swamp", ""},
+	{"Bonemass", CategoryCreature, "Swamp", ""},
+	{"Wolf", CategoryCreature, "Mountain", ""},
+	{"Hatchling", CategoryCreature, "Mountain", ""},
+	{"StoneGolem", CategoryCreature, "Mountain", ""},
+	{"Fenring", CategoryCreature, "Mountain", ""},
+	{"Dragon", CategoryCreature, "Mountain", ""},
+	{"Lox", CategoryCreature, "Plains", ""},
+	{"Deathsquito", CategoryCreature, "Plains", ""},
+	{"Goblin", CategoryCreature, "Plains", ""},
+	{"GoblinBrute", CategoryCreature, "Plains", ""},
+	{"GoblinShaman", CategoryCreature, "Plains", ""},
+	{"GoblinKing", CategoryCreature, "Plains", ""},
+	{"Serpent", CategoryCreature, "Ocean", ""},
+	{"Hen", CategoryCreature, "Mistlands", ""},
+	{"Chicken", CategoryCreature, "Mistlands", ""},
+
+	// Item drops
+	{"Wood", CategoryItem, "Meadows", ""},
+	{"Stone", CategoryItem, "Meadows", ""},
+	{"Flint", CategoryItem, "Meadows", ""},
+	{"Resin", CategoryItem, "Meadows", ""},
+	{"LeatherScraps", CategoryItem, "Meadows", ""},
+	{"DeerHide", CategoryItem, "Meadows", ""},
+	{"Feathers", CategoryItem, "Meadows", ""},
+	{"Raspberry", CategoryItem, "Meadows", ""},
+	{"Mushroom", CategoryItem, "Meadows", ""},
+	{"Dandelion", CategoryItem, "Meadows", ""},
+	{"BeechSeeds", CategoryItem, "Meadows", ""},
+	{"RoundLog", CategoryItem, "BlackForest", ""},
+	{"FineWood", CategoryItem, "Meadows", ""},
+	{"CopperOre", CategoryItem, "BlackForest", ""},
+	{"TinOre", CategoryItem, "BlackForest", ""},
+	{"Coal", CategoryItem, "BlackForest", ""},
+	{"BoneFragments", CategoryItem, "BlackForest", ""},
+	{"Blueberries", CategoryItem, "BlackForest", ""},
+	{"Thistle", CategoryItem, "BlackForest", ""},
+	{"TrollHide", CategoryItem, "BlackForest", ""},
+	{"PineCone", CategoryItem, "BlackForest", ""},
+	{"FirCone", CategoryItem, "BlackForest", ""},
+	{"ElderBark", CategoryItem, "Swamp", ""},
+	{"IronScrap", CategoryItem, "Swamp", ""},
+	{"AncientSeed", CategoryItem, "Swamp", ""},
+	{"SilverOre", CategoryItem, "Mountain", ""},
+	{"WolfPelt", CategoryItem, "Mountain", ""},
+	{"Obsidian", CategoryItem, "Mountain", ""},
+	{"BlackMetalScrap", CategoryItem, "Plains", ""},
+	{"LoxPelt", CategoryItem, "Plains", ""},
+	{"Barley", CategoryItem, "Plains", ""},
+	{"Flax", CategoryItem, "Plains", ""},
+
+	// Vegetation and resources
+	{"Beech1", CategoryVegetation, "Meadows", ""},
+	{"Beech_small1", CategoryVegetation, "Meadows", ""},
+	{"Beech_small2", CategoryVegetation, "Meadows", ""},
+	{"Beech_Stub", CategoryVegetation, "Meadows", ""},
+	{"Birch1", CategoryVegetation, "Meadows", ""},
+	{"Birch2", CategoryVegetation, "Meadows", ""},
+	{"Oak1", CategoryVegetation, "Meadows", ""},
+	{"Bush01", CategoryVegetation, "Meadows", ""},
+	{"Bush01_heath", CategoryVegetation, "Meadows", ""},
+	{"Bush02_en", CategoryVegetation, "BlackForest", ""},
+	{"RaspberryBush", CategoryVegetation, "Meadows", ""},
+	{"Pickable_Mushroom", CategoryVegetation, "Meadows", ""},
+	{"Pickable_Dandelion", CategoryVegetation, "Meadows", ""},
+	{"Pickable_Flint", CategoryVegetation, "Meadows", ""},
+	{"Pickable_Stone", CategoryVegetation, "Meadows", ""},
+	{"Pickable_Branch", CategoryVegetation, "Meadows", ""},
+	{"rock1", CategoryVegetation, "Meadows", ""},
+	{"rock2", CategoryVegetation, "Meadows", ""},
+	{"rock3", CategoryVegetation, "Meadows", ""},
+	{"rock4", CategoryVegetation, "Meadows", ""},
+	{"stubbe", CategoryVegetation, "Meadows", ""},
+	{"FirTree", CategoryVegetation, "BlackForest", ""},
+	{"FirTree_small", CategoryVegetation, "BlackForest", ""},
+	{"FirTree_small_dead", CategoryVegetation, "BlackForest", ""},
+	{"FirTree_Stub", CategoryVegetation, "BlackForest", ""},
+	{"Pinetree_01", CategoryVegetation, "BlackForest", ""},
+	{"Pinetree_01_Stub", CategoryVegetation, "BlackForest", ""},
+	{"BlueberryBush", CategoryVegetation, "BlackForest", ""},
+	{"Pickable_Thistle", CategoryVegetation, "BlackForest", ""},
+	{"MineRock_Copper", CategoryVegetation, "BlackForest", ""},
+	{"MineRock_Tin", CategoryVegetation, "BlackForest", ""},
+	{"SwampTree1", CategoryVegetation, "Swamp", ""},
+	{"SwampTree2", CategoryVegetation, "Swamp", ""},
+	{"MineRock_Obsidian", CategoryVegetation, "Mountain", ""},
+	{"CloudberryBush", CategoryVegetation, "Plains", ""},
+	{"MineRock_Meteorite", CategoryVegetation, "Ashlands", ""},
+
+	// Locations
+	{"LocationProxy", CategoryLocation, "", ""},
+	{"Spawner_GreydwarfNest", CategoryLocation, "BlackForest", ""},
+	{"Spawner_DraugrPile", CategoryLocation, "Swamp", ""},
+	{"BonePileSpawner", CategoryLocation, "BlackForest", ""},
+}
+
+// PrefabCatalog maps prefab hashes to prefab descriptions.
+type PrefabCatalog map[int]*PrefabInfo
+
+// NewPrefabCatalog returns a catalog of KnownPrefabs.
+func NewPrefabCatalog() PrefabCatalog {
+	c := make(PrefabCatalog, len(KnownPrefabs))
+	for i := range KnownPrefabs {
+		info := KnownPrefabs[i]
+		c.Add(&info)
+	}
+	return c
+}
+
+// Prefabs is the catalog used to resolve ZDO prefabs.
+var Prefabs = NewPrefabCatalog()
+
+// Add adds a prefab to the catalog, replacing any prefab with the same name.
+func (c PrefabCatalog) Add(info *PrefabInfo) {
+	c[StableHashCode(info.Name)] = info
+}
+
+// LoadFile adds the prefabs of a JSON or YAML file, holding a list of
+// prefabs in the format of PrefabInfo. Files with a .yaml or .yml
+// extension are decoded as YAML, other files as JSON. It is used to
+// describe modded prefabs or to override known prefabs.
+func (c PrefabCatalog) LoadFile(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("cannot read prefab catalog: %w", err)
+	}
+
+	var infos []*PrefabInfo
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &infos)
+	default:
+		err = json.Unmarshal(data, &infos)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot decode prefab catalog: %w", err)
+	}
+	for i, info := range infos {
+		if info.Name == "" {
+			return fmt.Errorf("prefab #%d has no name", i)
+		}
+		c.Add(info)
+	}
+
+	return nil
+}
+
+// Name returns the name of a prefab, or an empty string when the prefab is
+// not in the catalog.
+func (c PrefabCatalog) Name(prefab int) string {
+	if info, ok := c[prefab]; ok {
+		return info.Name
+	}
+	return ""
+}
+
+// Hash of the ZDO property holding the player ID of a piece creator.
+var creatorHash = StableHashCode("creator")
+
+// Category returns the category of the ZDO. Pieces missing from the Prefabs
+// catalog are considered buildings when they were placed by a player.
+func (zdo *ZDO) Category() PrefabCategory {
+	if info, ok := Prefabs[zdo.Prefab]; ok {
+		return info.Category
+	}
+	if _, ok := zdo.LookupLongHash(creatorHash); ok {
+		return CategoryBuilding
+	}
+	return CategoryUnknown
+}
@@ -0,0 +1,211 @@
+package vhpackage
+
+// Typed accessors to ZDO properties, mirroring the game ZDO API.
+// Property names are hashed with StableHashCode; getters return def when
+// the property is not set. The Hash variants take the hash of the property
+// name, to avoid hashing it again for each ZDO in loops.
+
+func (zdo *ZDO) GetFloat(name string, def float32) float32 {
+	return zdo.GetFloatHash(StableHashCode(name), def)
+}
+
+func (zdo *ZDO) GetFloatHash(hash int, def float32) float32 {
+	if v, ok := zdo.Floats[hash]; ok {
+		return v
+	}
+	return def
+}
+
+func (zdo *ZDO) SetFloat(name string, v float32) {
+	zdo.SetFloatHash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetFloatHash(hash int, v float32) {
+	if zdo.Floats == nil {
+		zdo.Floats = make(map[int]float32)
+	}
+	zdo.Floats[hash] = v
+}
+
+func (zdo *ZDO) GetVec3(name string, def Vector3) Vector3 {
+	return zdo.GetVec3Hash(StableHashCode(name), def)
+}
+
+func (zdo *ZDO) GetVec3Hash(hash int, def Vector3) Vector3 {
+	if v, ok := zdo.Vectors[hash]; ok {
+		return v
+	}
+	return def
+}
+
+func (zdo *ZDO) SetVec3(name string, v Vector3) {
+	zdo.SetVec3Hash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetVec3Hash(hash int, v Vector3) {
+	if zdo.Vectors == nil {
+		zdo.Vectors = make(map[int]Vector3)
+	}
+	zdo.Vectors[hash] = v
+}
+
+func (zdo *ZDO) GetQuaternion(name string, def Quaternion) Quaternion {
+	return zdo.GetQuaternionHash(StableHashCode(name), def)
+}
+
+func (zdo *ZDO) GetQuaternionHash(hash int, def Quaternion) Quaternion {
+	if v, ok := zdo.Quaternions[hash]; ok {
+		return v
+	}
+	return def
+}
+
+func (zdo *ZDO) SetQuaternion(name string, v Quaternion) {
+	zdo.SetQuaternionHash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetQuaternionHash(hash int, v Quaternion) {
+	if zdo.Quaternions == nil {
+		zdo.Quaternions = make(map[int]Quaternion)
+	}
+	zdo.Quaternions[hash] = v
+}
+
+func (zdo *ZDO) GetInt(name string, def int) int {
+	return zdo.GetIntHash(StableHashCode(name), def)
+}
+
+func (zdo *ZDO) GetIntHash(hash int, def int) int {
+	if v, ok := zdo.Ints[hash]; ok {
+		return v
+	}
+	return def
+}
+
+func (zdo *ZDO) SetInt(name string, v int) {
+	zdo.SetIntHash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetIntHash(hash int, v int) {
+	if zdo.Ints == nil {
+		zdo.Ints = make(map[int]int)
+	}
+	zdo.Ints[hash] = v
+}
+
+// GetBool reads a boolean, stored by the game as an int.
+func (zdo *ZDO) GetBool(name string, def bool) bool {
+	return zdo.GetBoolHash(StableHashCode(name), def)
+}
+
+func (zdo *ZDO) GetBoolHash(hash int, def bool) bool {
+	if v, ok := zdo.Ints[hash]; ok {
+		return v != 0
+	}
+	return def
+}
+
+func (zdo *ZDO) SetBool(name string, v bool) {
+	zdo.SetBoolHash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetBoolHash(hash int, v bool) {
+	i := 0
+	if v {
+		i = 1
+	}
+	zdo.SetIntHash(hash, i)
+}
+
+func (zdo *ZDO) GetLong(name string, def int64) int64 {
+	return zdo.GetLongHash(StableHashCode(name), def)
+}
+
+func (zdo *ZDO) GetLongHash(hash int, def int64) int64 {
+	if v, ok := zdo.LookupLongHash(hash); ok {
+		return v
+	}
+	return def
+}
+
+// LookupLong returns a long property and whether it is set.
+func (zdo *ZDO) LookupLong(name string) (int64, bool) {
+	return zdo.LookupLongHash(StableHashCode(name))
+}
+
+func (zdo *ZDO) LookupLongHash(hash int) (int64, bool) {
+	v, ok := zdo.Longs[hash]
+	return v, ok
+}
+
+func (zdo *ZDO) SetLong(name string, v int64) {
+	zdo.SetLongHash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetLongHash(hash int, v int64) {
+	if zdo.Longs == nil {
+		zdo.Longs = make(map[int]int64)
+	}
+	zdo.Longs[hash] = v
+}
+
+// GetZDOID reads a ZDOID, stored by the game as two longs named name+"_u"
+// and name+"_i". It returns the zero ZDOID when either is not set.
+func (zdo *ZDO) GetZDOID(name string) ZDOID {
+	userID, ok := zdo.Longs[StableHashCode(name+"_u")]
+	if !ok {
+		return ZDOID{}
+	}
+	id, ok := zdo.Longs[StableHashCode(name+"_i")]
+	if !ok {
+		return ZDOID{}
+	}
+	return ZDOID{UserID: userID, ID: uint32(id)}
+}
+
+func (zdo *ZDO) SetZDOID(name string, v ZDOID) {
+	zdo.SetLong(name+"_u", v.UserID)
+	zdo.SetLong(name+"_i", int64(v.ID))
+}
+
+func (zdo *ZDO) GetString(name string, def string) string {
+	return zdo.GetStringHash(StableHashCode(name), def)
+}
+
+func (zdo *ZDO) GetStringHash(hash int, def string) string {
+	if v, ok := zdo.Strings[hash]; ok {
+		return v
+	}
+	return def
+}
+
+func (zdo *ZDO) SetString(name string, v string) {
+	zdo.SetStringHash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetStringHash(hash int, v string) {
+	if zdo.Strings == nil {
+		zdo.Strings = make(map[int]string)
+	}
+	zdo.Strings[hash] = v
+}
+
+// GetByteArray returns nil when the property is not set.
+func (zdo *ZDO) GetByteArray(name string) []byte {
+	return zdo.GetByteArrayHash(StableHashCode(name))
+}
+
+func (zdo *ZDO) GetByteArrayHash(hash int) []byte {
+	return zdo.ByteArrays[hash]
+}
+
+func (zdo *ZDO) SetByteArray(name string, v []byte) {
+	zdo.SetByteArrayHash(StableHashCode(name), v)
+}
+
+func (zdo *ZDO) SetByteArrayHash(hash int, v []byte) {
+	if zdo.ByteArrays == nil {
+		zdo.ByteArrays = make(map[int][]byte)
+	}
+	zdo.ByteArrays[hash] = v
+}
@@ -0,0 +1,130 @@
+package vhpackage
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Size of a zone in world units.
+const ZoneSize = 64
+
+// WorldRadius is the radius of the playable world in world units.
+const WorldRadius = 10500
+
+// RenderOptions configures world map rendering.
+type RenderOptions struct {
+	// Scale is the number of world units per pixel.
+	Scale float32
+	// Min and Max delimit the rendered area in world coordinates (X, Z).
+	Min, Max Vector2
+}
+
+// DefaultRenderOptions renders the whole world with 8 world units per pixel.
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{
+		Scale: 8,
+		Min:   Vector2{-WorldRadius, -WorldRadius},
+		Max:   Vector2{WorldRadius, WorldRadius},
+	}
+}
+
+var (
+	renderBackground    = color.RGBA{20, 20, 30, 255}
+	renderZone          = color.RGBA{45, 65, 45, 255}
+	renderLocation      = color.RGBA{230, 230, 230, 255}
+	renderCategoryColor = map[PrefabCategory]color.RGBA{
+		CategoryBuilding:  {170, 120, 70, 255},
+		CategoryContainer: {230, 200, 40, 255},
+		CategoryPortal:    {220, 60, 220, 255},
+		CategoryBed:       {60, 140, 240, 255},
+		CategoryTombstone: {230, 40, 40, 255},
+		CategorySign:      {240, 240, 240, 255},
+	}
+)
+
+// Render draws a top-down map of the world: generated zones, location
+// instances and ZDOs colored by category.
+func (w *World) Render(opts RenderOptions) (*image.RGBA, error) {
+	r, err := newRenderer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, zone := range w.GeneratedZones {
+		r.zone(zone, renderZone)
+	}
+
+	// Buildings first, so that notable pieces are drawn over them.
+	for _, zdo := range w.ZDOs {
+		if zdo.Category() == CategoryBuilding {
+			r.point(zdo.Position.X, zdo.Position.Z, 0, renderCategoryColor[CategoryBuilding])
+		}
+	}
+	for _, zdo := range w.ZDOs {
+		c := zdo.Category()
+		if c == CategoryBuilding || !c.IsPiece() {
+			continue
+		}
+		r.point(zdo.Position.X, zdo.Position.Z, 1, renderCategoryColor[c])
+	}
+
+	for _, loc := range w.LocationInstances {
+		r.cross(loc.Position.X, loc.Position.Z, 2, renderLocation)
+	}
+
+	return r.img, nil
+}
+
+type renderer struct {
+	opts RenderOptions
+	img  *image.RGBA
+}
+
+func newRenderer(opts RenderOptions) (*renderer, error) {
+	if opts.Scale <= 0 {
+		return nil, fmt.Errorf("invalid scale %v", opts.Scale)
+	}
+	if opts.Max.X <= opts.Min.X || opts.Max.Y <= opts.Min.Y {
+		return nil, fmt.Errorf("invalid crop area %v - %v", opts.Min, opts.Max)
+	}
+
+	width := int((opts.Max.X - opts.Min.X) / opts.Scale)
+	height := int((opts.Max.Y - opts.Min.Y) / opts.Scale)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{renderBackground}, image.Point{}, draw.Src)
+
+	return &renderer{opts: opts, img: img}, nil
+}
+
+// pixel converts world coordinates to image coordinates.
+func (r *renderer) pixel(x, z float32) (int, int) {
+	return int((x - r.opts.Min.X) / r.opts.Scale), int((r.opts.Max.Y - z) / r.opts.Scale)
+}
+
+func (r *renderer) zone(zone Vector2i, c color.RGBA) {
+	// Zone coordinates are the center of the zone.
+	cx, cz := float32(zone.X)*ZoneSize, float32(zone.Y)*ZoneSize
+	x0, y0 := r.pixel(cx-ZoneSize/2, cz+ZoneSize/2)
+	x1, y1 := r.pixel(cx+ZoneSize/2, cz-ZoneSize/2)
+	draw.Draw(r.img, image.Rect(x0, y0, x1, y1), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// point draws a square of 2*size+1 pixels centered on a world position.
+func (r *renderer) point(x, z float32, size int, c color.RGBA) {
+	px, py := r.pixel(x, z)
+	for dy := -size; dy <= size; dy++ {
+		for dx := -size; dx <= size; dx++ {
+			r.img.SetRGBA(px+dx, py+dy, c)
+		}
+	}
+}
+
+func (r *renderer) cross(x, z float32, size int, c color.RGBA) {
+	px, py := r.pixel(x, z)
+	for d := -size; d <= size; d++ {
+		r.img.SetRGBA(px+d, py, c)
+		r.img.SetRGBA(px, py+d, c)
+	}
+}
@@ -0,0 +1,114 @@
+package vhpackage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Sign is a sign placed in the world.
+type Sign struct {
+	UID      ZDOID   `json:"uid"`
+	Text     string  `json:"text"`
+	Author   string  `json:"author,omitempty"`
+	Position Vector3 `json:"position"`
+	// Matches lists the blocklist patterns matching the text.
+	Matches []string `json:"matches,omitempty"`
+}
+
+var (
+	signTextHash   = StableHashCode("text")
+	signAuthorHash = StableHashCode("author")
+)
+
+// Signs returns the signs of the world, sorted by ZDOID.
+func (w *World) Signs() []*Sign {
+	signs := []*Sign{}
+	for _, zdo := range w.ZDOs {
+		if zdo.Category() != CategorySign {
+			continue
+		}
+
+		signs = append(signs, &Sign{
+			UID:      zdo.UID,
+			Text:     zdo.GetStringHash(signTextHash, ""),
+			Author:   zdo.GetStringHash(signAuthorHash, ""),
+			Position: zdo.Position,
+		})
+	}
+
+	sort.Slice(signs, func(i, j int) bool { return signs[i].UID.Less(signs[j].UID) })
+	return signs
+}
+
+// SetSignText replaces the text of a sign. An empty text blanks the sign.
+func (w *World) SetSignText(uid ZDOID, text string) error {
+	for _, zdo := range w.ZDOs {
+		if zdo.UID != uid {
+			continue
+		}
+		if zdo.Category() != CategorySign {
+			return fmt.Errorf("ZDO %s is not a sign", uid)
+		}
+
+		zdo.SetStringHash(signTextHash, text)
+		return nil
+	}
+	return fmt.Errorf("sign %s not found", uid)
+}
+
+// SignBlocklist is a list of regular expressions matched against sign text.
+type SignBlocklist []*regexp.Regexp
+
+// NewSignBlocklistFromFile reads a blocklist with one regular expression
+// per line. Empty lines and lines starting with # are ignored.
+// Patterns are matched case insensitively.
+func NewSignBlocklistFromFile(file string) (SignBlocklist, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open blocklist: %w", err)
+	}
+	defer f.Close()
+
+	var blocklist SignBlocklist
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern on line %d: %w", line, err)
+		}
+		blocklist = append(blocklist, re)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read blocklist: %w", err)
+	}
+
+	return blocklist, nil
+}
+
+// Match sets the Matches of each sign and returns the signs matching at
+// least one pattern.
+func (b SignBlocklist) Match(signs []*Sign) []*Sign {
+	matched := []*Sign{}
+	for _, sign := range signs {
+		sign.Matches = nil
+		for _, re := range b {
+			if re.MatchString(sign.Text) {
+				// Strip the case insensitive flag added when loading.
+				sign.Matches = append(sign.Matches, strings.TrimPrefix(re.String(), "(?i)"))
+			}
+		}
+		if len(sign.Matches) > 0 {
+			matched = append(matched, sign)
+		}
+	}
+	return matched
+}
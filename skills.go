@@ -0,0 +1,57 @@
+package vhpackage
+
+import "fmt"
+
+// SkillType represents Skills.SkillType enum.
+type SkillType int
+
+const (
+	SkillNone        SkillType = 0
+	SkillSwords      SkillType = 1
+	SkillKnives      SkillType = 2
+	SkillClubs       SkillType = 3
+	SkillPolearms    SkillType = 4
+	SkillSpears      SkillType = 5
+	SkillBlocking    SkillType = 6
+	SkillAxes        SkillType = 7
+	SkillBows        SkillType = 8
+	SkillFireMagic   SkillType = 9
+	SkillFrostMagic  SkillType = 10
+	SkillUnarmed     SkillType = 11
+	SkillPickaxes    SkillType = 12
+	SkillWoodCutting SkillType = 13
+	SkillJump        SkillType = 100
+	SkillSneak       SkillType = 101
+	SkillRun         SkillType = 102
+	SkillSwim        SkillType = 103
+	SkillAll         SkillType = 999
+)
+
+var skillNames = map[SkillType]string{
+	SkillNone:        "None",
+	SkillSwords:      "Swords",
+	SkillKnives:      "Knives",
+	SkillClubs:       "Clubs",
+	SkillPolearms:    "Polearms",
+	SkillSpears:      "Spears",
+	SkillBlocking:    "Blocking",
+	SkillAxes:        "Axes",
+	SkillBows:        "Bows",
+	SkillFireMagic:   "FireMagic",
+	SkillFrostMagic:  "FrostMagic",
+	SkillUnarmed:     "Unarmed",
+	SkillPickaxes:    "Pickaxes",
+	SkillWoodCutting: "WoodCutting",
+	SkillJump:        "Jump",
+	SkillSneak:       "Sneak",
+	SkillRun:         "Run",
+	SkillSwim:        "Swim",
+	SkillAll:         "All",
+}
+
+func (t SkillType) String() string {
+	if name, ok := skillNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("SkillType(%d)", int(t))
+}
@@ -0,0 +1,114 @@
+package vhpackage
+
+import (
+	"sort"
+	"strconv"
+)
+
+// zdoTypeNames are the names of ZDO types, as defined by the game.
+var zdoTypeNames = map[int8]string{
+	0: "default",
+	1: "prioritized",
+	2: "solid",
+	3: "terrain",
+}
+
+// SectorCount is the number of ZDOs of a sector.
+type SectorCount struct {
+	Sector Vector2i `json:"sector"`
+	ZDOs   int      `json:"zdos"`
+}
+
+// WorldStats summarizes the content of a world.
+type WorldStats struct {
+	ZDOs          int `json:"zdos"`
+	Persistent    int `json:"persistent"`
+	NonPersistent int `json:"non_persistent"`
+
+	ByType     map[string]int `json:"by_type"`
+	ByCategory map[string]int `json:"by_category"`
+	// ByMaterial counts pieces by building material.
+	ByMaterial map[string]int `json:"by_material"`
+	// ByPrefab counts ZDOs by prefab name, or prefab hash when the prefab
+	// is not in the catalog.
+	ByPrefab map[string]int `json:"by_prefab"`
+
+	TopSectors []SectorCount `json:"top_sectors"`
+
+	GeneratedZones int `json:"generated_zones"`
+	DeadZDOs       int `json:"dead_zdos"`
+	// Days is the age of the world in in-game days.
+	Days float64 `json:"days"`
+	// Day and TimeOfDay are the current in-game day and fraction of day.
+	Day       int     `json:"day"`
+	TimeOfDay float64 `json:"time_of_day"`
+}
+
+// Stats summarizes the world, with the top sectors having the most ZDOs.
+func (w *World) Stats(top int) *WorldStats {
+	s := &WorldStats{
+		ZDOs:           len(w.ZDOs),
+		ByType:         make(map[string]int),
+		ByCategory:     make(map[string]int),
+		ByMaterial:     make(map[string]int),
+		ByPrefab:       make(map[string]int),
+		GeneratedZones: len(w.GeneratedZones),
+		DeadZDOs:       len(w.DeadZDOs),
+		Days:           w.NetTime / DayLength,
+		Day:            GameDay(w.Uptime()),
+		TimeOfDay:      TimeOfDay(w.Uptime()),
+	}
+
+	sectors := make(map[Vector2i]int)
+	for _, zdo := range w.ZDOs {
+		if zdo.Persistent {
+			s.Persistent++
+		} else {
+			s.NonPersistent++
+		}
+
+		typ, ok := zdoTypeNames[zdo.Type]
+		if !ok {
+			typ = strconv.Itoa(int(zdo.Type))
+		}
+		s.ByType[typ]++
+
+		category := zdo.Category()
+		if category == CategoryUnknown {
+			s.ByCategory["unknown"]++
+		} else {
+			s.ByCategory[string(category)]++
+		}
+
+		if info, ok := Prefabs[zdo.Prefab]; ok {
+			s.ByPrefab[info.Name]++
+			if category.IsPiece() && info.Material != "" {
+				s.ByMaterial[info.Material]++
+			}
+		} else {
+			s.ByPrefab[strconv.Itoa(zdo.Prefab)]++
+		}
+
+		sectors[zdo.Sector]++
+	}
+
+	s.TopSectors = make([]SectorCount, 0, len(sectors))
+	for sector, n := range sectors {
+		s.TopSectors = append(s.TopSectors, SectorCount{Sector: sector, ZDOs: n})
+	}
+	sort.Slice(s.TopSectors, func(i, j int) bool {
+		a, b := s.TopSectors[i], s.TopSectors[j]
+		if a.ZDOs != b.ZDOs {
+			return a.ZDOs > b.ZDOs
+		}
+		if a.Sector.X != b.Sector.X {
+			return a.Sector.X < b.Sector.X
+		}
+		return a.Sector.Y < b.Sector.Y
+	})
+	if top >= 0 && top < len(s.TopSectors) {
+		s.TopSectors = s.TopSectors[:top]
+	}
+
+	return s
+}
@@ -0,0 +1,273 @@
+package vhpackage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"sort"
+)
+
+// TerrainCompWidth is the number of vertices on each side of the terrain
+// modification grid of a zone, minus one.
+const TerrainCompWidth = ZoneSize
+
+// Color is a color with components between 0 and 1.
+type Color struct {
+	R float32 `json:"r"`
+	G float32 `json:"g"`
+	B float32 `json:"b"`
+	A float32 `json:"a"`
+}
+
+// TerrainComp holds the terrain modifications of a zone: height deltas and
+// paint of each vertex of a (TerrainCompWidth+1)² grid centered on the zone.
+// Vertices are stored row by row, from the south-west corner.
+type TerrainComp struct {
+	Zone Vector2i `json:"zone"`
+
+	Version      int     `json:"version"`
+	Operations   int     `json:"operations"`
+	LastOpPoint  Vector3 `json:"last_op_point"`
+	LastOpRadius float32 `json:"last_op_radius"`
+
+	Modified    []bool    `json:"modified"`
+	LevelDelta  []float32 `json:"level_delta"`
+	SmoothDelta []float32 `json:"smooth_delta"`
+
+	PaintModified []bool  `json:"paint_modified"`
+	Paint         []Color `json:"paint"`
+}
+
+// DecodeTerrainComp decodes the compressed terrain modifications stored by
+// the game in the TCData property of a TerrainComp ZDO.
+// The zone of the returned TerrainComp is not set.
+func DecodeTerrainComp(data []byte) (*TerrainComp, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress terrain data: %w", err)
+	}
+	raw, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress terrain data: %w", err)
+	}
+
+	t := &TerrainComp{}
+	if err := t.read(NewZPackageFromData(raw)); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *TerrainComp) read(pkg *ZPackage) error {
+	var err error
+
+	t.Version, err = pkg.ReadInt()
+	if err != nil {
+		return fmt.Errorf("cannot read terrain version: %w", err)
+	}
+	t.Operations, err = pkg.ReadInt()
+	if err != nil {
+		return fmt.Errorf("cannot read terrain operations: %w", err)
+	}
+	t.LastOpPoint, err = pkg.ReadVector3()
+	if err != nil {
+		return fmt.Errorf("cannot read terrain last operation point: %w", err)
+	}
+	t.LastOpRadius, err = pkg.ReadSingle()
+	if err != nil {
+		return fmt.Errorf("cannot read terrain last operation radius: %w", err)
+	}
+
+	n, err := pkg.ReadInt()
+	if err != nil {
+		return fmt.Errorf("cannot read terrain heights: %w", err)
+	}
+	if n != terrainVertices {
+		return fmt.Errorf("invalid terrain heights count %d", n)
+	}
+	t.Modified = make([]bool, n)
+	t.LevelDelta = make([]float32, n)
+	t.SmoothDelta = make([]float32, n)
+	for i := 0; i < n; i++ {
+		t.Modified[i], err = pkg.ReadBool()
+		if err != nil {
+			return fmt.Errorf("cannot read terrain height #%d: %w", i, err)
+		}
+		if !t.Modified[i] {
+			continue
+		}
+		t.LevelDelta[i], err = pkg.ReadSingle()
+		if err != nil {
+			return fmt.Errorf("cannot read terrain height #%d: %w", i, err)
+		}
+		t.SmoothDelta[i], err = pkg.ReadSingle()
+		if err != nil {
+			return fmt.Errorf("cannot read terrain height #%d: %w", i, err)
+		}
+	}
+
+	n, err = pkg.ReadInt()
+	if err != nil {
+		return fmt.Errorf("cannot read terrain paint: %w", err)
+	}
+	if n != terrainVertices {
+		return fmt.Errorf("invalid terrain paint count %d", n)
+	}
+	t.PaintModified = make([]bool, n)
+	t.Paint = make([]Color, n)
+	for i := 0; i < n; i++ {
+		t.PaintModified[i], err = pkg.ReadBool()
+		if err != nil {
+			return fmt.Errorf("cannot read terrain paint #%d: %w", i, err)
+		}
+		if !t.PaintModified[i] {
+			continue
+		}
+		c := &t.Paint[i]
+		for _, f := range []*float32{&c.R, &c.G, &c.B, &c.A} {
+			*f, err = pkg.ReadSingle()
+			if err != nil {
+				return fmt.Errorf("cannot read terrain paint #%d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+const terrainVertices = (TerrainCompWidth + 1) * (TerrainCompWidth + 1)
+
+// Hash of the ZDO byte array property holding terrain modifications.
+var terrainDataHash = StableHashCode("TCData")
+
+// TerrainComps decodes the terrain modifications of the world, most
+// modified zones first.
+func (w *World) TerrainComps() ([]*TerrainComp, error) {
+	comps := []*TerrainComp{}
+	for _, zdo := range w.ZDOs {
+		data := zdo.GetByteArrayHash(terrainDataHash)
+		if data == nil {
+			continue
+		}
+
+		t, err := DecodeTerrainComp(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid terrain of ZDO %s: %w", zdo.UID, err)
+		}
+		t.Zone = zdo.Sector
+		comps = append(comps, t)
+	}
+
+	sort.SliceStable(comps, func(i, j int) bool {
+		return comps[i].ModifiedVertices() > comps[j].ModifiedVertices()
+	})
+	return comps, nil
+}
+
+// ModifiedVertices returns the number of vertices with a modified height.
+func (t *TerrainComp) ModifiedVertices() int {
+	return countTrue(t.Modified)
+}
+
+// PaintedVertices returns the number of vertices with a modified paint.
+func (t *TerrainComp) PaintedVertices() int {
+	return countTrue(t.PaintModified)
+}
+
+func countTrue(l []bool) int {
+	n := 0
+	for _, b := range l {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+// HeightDelta returns the height change of vertex i.
+func (t *TerrainComp) HeightDelta(i int) float32 {
+	return t.LevelDelta[i] + t.SmoothDelta[i]
+}
+
+// VertexPosition returns the world coordinates (X, Z) of vertex i.
+func (t *TerrainComp) VertexPosition(i int) (float32, float32) {
+	const size = TerrainCompWidth + 1
+	x := float32(t.Zone.X)*ZoneSize - TerrainCompWidth/2 + float32(i%size)
+	z := float32(t.Zone.Y)*ZoneSize - TerrainCompWidth/2 + float32(i/size)
+	return x, z
+}
+
+// HeightImage draws the height deltas of the zone, north up.
+// Raised vertices are red, lowered vertices are blue and unmodified
+// vertices are transparent.
+func (t *TerrainComp) HeightImage() *image.RGBA {
+	return t.image(t.Modified, func(i int) color.RGBA { return heightDeltaColor(t.HeightDelta(i)) })
+}
+
+// PaintImage draws the paint of the zone, north up. Unpainted vertices are
+// transparent.
+func (t *TerrainComp) PaintImage() *image.RGBA {
+	return t.image(t.PaintModified, func(i int) color.RGBA {
+		c := t.Paint[i]
+		return color.RGBA{colorComponent(c.R), colorComponent(c.G), colorComponent(c.B), 255}
+	})
+}
+
+func (t *TerrainComp) image(modified []bool, vertexColor func(i int) color.RGBA) *image.RGBA {
+	const size = TerrainCompWidth + 1
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for i, m := range modified {
+		if m {
+			img.SetRGBA(i%size, size-1-i/size, vertexColor(i))
+		}
+	}
+	return img
+}
+
+// RenderTerrain draws the height deltas of terrain modifications over the
+// area of opts, using the colors of HeightImage.
+func RenderTerrain(comps []*TerrainComp, opts RenderOptions) (*image.RGBA, error) {
+	r, err := newRenderer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range comps {
+		for i, m := range t.Modified {
+			if m {
+				x, z := t.VertexPosition(i)
+				r.point(x, z, 0, heightDeltaColor(t.HeightDelta(i)))
+			}
+		}
+	}
+
+	return r.img, nil
+}
+
+// heightDeltaColor maps height deltas up to 16 units to a color.
+func heightDeltaColor(delta float32) color.RGBA {
+	v := delta / 16
+	if v > 1 {
+		v = 1
+	}
+	if v < -1 {
+		v = -1
+	}
+	if v >= 0 {
+		return color.RGBA{uint8(80 + 175*v), 80, 80, 255}
+	}
+	return color.RGBA{80, 80, uint8(80 - 175*v), 255}
+}
+
+func colorComponent(f float32) uint8 {
+	if f <= 0 {
+		return 0
+	}
+	if f >= 1 {
+		return 255
+	}
+	return uint8(f * 255)
+}
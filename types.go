@@ -1,8 +1,13 @@
 package vhpackage
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // ZDO represents a data object.
@@ -31,6 +36,40 @@ type ZDO struct {
 	Ints        map[int]int        `json:"ints"`
 	Longs       map[int]int64      `json:"longs"`
 	Strings     map[int]string     `json:"strings"`
+	ByteArrays  map[int][]byte     `json:"byte_arrays,omitempty"` // Only version >= 27
+}
+
+// zdoJSON has the fields of ZDO without its methods.
+type zdoJSON ZDO
+
+// zdoJSONOutput adds fields derived from the ZDO to its JSON encoding.
+type zdoJSONOutput struct {
+	zdoJSON
+	// DayCreated is the in-game day of TimeCreated. It is ignored when
+	// decoding.
+	DayCreated int `json:"day_created"`
+}
+
+// MarshalJSON encodes the ZDO with its in-game creation day.
+func (zdo *ZDO) MarshalJSON() ([]byte, error) {
+	return json.Marshal(zdoJSONOutput{
+		zdoJSON:    zdoJSON(*zdo),
+		DayCreated: GameDay(TicksDuration(zdo.TimeCreated)),
+	})
+}
+
+// UnmarshalJSON decodes a ZDO encoded by MarshalJSON. Unknown fields are
+// rejected.
+func (zdo *ZDO) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var v zdoJSONOutput
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	*zdo = ZDO(v.zdoJSON)
+	return nil
 }
 
 func (zdo *ZDO) LoadZDO(pkg *ZPackage, version int) error {
@@ -223,6 +262,197 @@ func (zdo *ZDO) LoadZDO(pkg *ZPackage, version int) error {
 		}
 	}
 
+	// Byte arrays
+	if version >= 27 {
+		c, err = pkg.ReadChar()
+		if err != nil {
+			return fmt.Errorf("cannot read number of byte arrays: %w", err)
+		}
+		num = int(c)
+		if num > 0 {
+			zdo.ByteArrays = make(map[int][]byte)
+			for i := 0; i < num; i++ {
+				key, err := pkg.ReadInt()
+				if err != nil {
+					return fmt.Errorf("cannot read byte array key: %w", err)
+				}
+				zdo.ByteArrays[key], err = pkg.ReadByteArray()
+				if err != nil {
+					return fmt.Errorf("cannot read byte array value: %w", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// SaveZDO writes the ZDO in the format read by LoadZDO for the given world version.
+func (zdo *ZDO) SaveZDO(pkg *ZPackage, version int) error {
+	if err := pkg.WriteUInt(zdo.OwnerRevision); err != nil {
+		return fmt.Errorf("cannot write owner revision: %w", err)
+	}
+	if err := pkg.WriteUInt(zdo.DataRevision); err != nil {
+		return fmt.Errorf("cannot write data revision: %w", err)
+	}
+	if err := pkg.WriteBool(zdo.Persistent); err != nil {
+		return fmt.Errorf("cannot write persistent: %w", err)
+	}
+	if err := pkg.WriteLong(zdo.Owner); err != nil {
+		return fmt.Errorf("cannot write owner: %w", err)
+	}
+	if err := pkg.WriteLong(zdo.TimeCreated); err != nil {
+		return fmt.Errorf("cannot write time created: %w", err)
+	}
+	if err := pkg.WriteInt(zdo.PGWVersion); err != nil {
+		return fmt.Errorf("cannot write PGW version: %w", err)
+	}
+
+	if version >= 16 && version < 24 {
+		if err := pkg.WriteInt(0); err != nil {
+			return fmt.Errorf("cannot write skipped int: %w", err)
+		}
+	}
+	if version >= 23 {
+		if err := pkg.WriteSByte(zdo.Type); err != nil {
+			return fmt.Errorf("cannot write ZDO type: %w", err)
+		}
+	}
+	if version >= 22 {
+		if err := pkg.WriteBool(zdo.Distant); err != nil {
+			return fmt.Errorf("cannot write distant: %w", err)
+		}
+	}
+	if version < 13 {
+		pkg.WriteChar(0)
+		pkg.WriteChar(0)
+	}
+	if version >= 17 {
+		if err := pkg.WriteInt(zdo.Prefab); err != nil {
+			return fmt.Errorf("cannot write prefab: %w", err)
+		}
+	}
+
+	if err := pkg.WriteVector2i(zdo.Sector); err != nil {
+		return fmt.Errorf("cannot write sector: %w", err)
+	}
+	if err := pkg.WriteVector3(zdo.Position); err != nil {
+		return fmt.Errorf("cannot write position: %w", err)
+	}
+	if err := pkg.WriteQuaternion(zdo.Rotation); err != nil {
+		return fmt.Errorf("cannot write rotation: %w", err)
+	}
+
+	// Floats
+	keys := make([]int, 0, len(zdo.Floats))
+	for k := range zdo.Floats {
+		keys = append(keys, k)
+	}
+	if err := writeProperties(pkg, "floats", keys, func(k int) error {
+		return pkg.WriteSingle(zdo.Floats[k])
+	}); err != nil {
+		return err
+	}
+
+	// Vector3s
+	keys = keys[:0]
+	for k := range zdo.Vectors {
+		keys = append(keys, k)
+	}
+	if err := writeProperties(pkg, "vector3s", keys, func(k int) error {
+		return pkg.WriteVector3(zdo.Vectors[k])
+	}); err != nil {
+		return err
+	}
+
+	// Quaternions
+	keys = keys[:0]
+	for k := range zdo.Quaternions {
+		keys = append(keys, k)
+	}
+	if err := writeProperties(pkg, "quaternions", keys, func(k int) error {
+		return pkg.WriteQuaternion(zdo.Quaternions[k])
+	}); err != nil {
+		return err
+	}
+
+	// Ints
+	keys = keys[:0]
+	for k := range zdo.Ints {
+		keys = append(keys, k)
+	}
+	if err := writeProperties(pkg, "ints", keys, func(k int) error {
+		return pkg.WriteInt(zdo.Ints[k])
+	}); err != nil {
+		return err
+	}
+
+	// Longs
+	keys = keys[:0]
+	for k := range zdo.Longs {
+		keys = append(keys, k)
+	}
+	if err := writeProperties(pkg, "longs", keys, func(k int) error {
+		return pkg.WriteLong(zdo.Longs[k])
+	}); err != nil {
+		return err
+	}
+
+	// Strings
+	keys = keys[:0]
+	for k := range zdo.Strings {
+		keys = append(keys, k)
+	}
+	if err := writeProperties(pkg, "strings", keys, func(k int) error {
+		return pkg.WriteString(zdo.Strings[k])
+	}); err != nil {
+		return err
+	}
+
+	// Byte arrays
+	if version < 27 {
+		if len(zdo.ByteArrays) > 0 {
+			return fmt.Errorf("byte arrays are not supported by world version %d", version)
+		}
+		return nil
+	}
+	keys = keys[:0]
+	for k := range zdo.ByteArrays {
+		keys = append(keys, k)
+	}
+	if err := writeProperties(pkg, "byte arrays", keys, func(k int) error {
+		return pkg.WriteByteArray(zdo.ByteArrays[k])
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// maxProperties is the largest number of entries of a property map, which
+// is written as a char: higher values reach the UTF-16 surrogate halves.
+const maxProperties = 0xD7FF
+
+// writeProperties writes a property map: its number of entries as a char,
+// then each key followed by its value.
+func writeProperties(pkg *ZPackage, name string, keys []int, writeValue func(key int) error) error {
+	if len(keys) > maxProperties {
+		return fmt.Errorf("too many %s: %d", name, len(keys))
+	}
+	if err := pkg.WriteChar(uint16(len(keys))); err != nil {
+		return fmt.Errorf("cannot write number of %s: %w", name, err)
+	}
+
+	sort.Ints(keys)
+	for _, k := range keys {
+		if err := pkg.WriteInt(k); err != nil {
+			return fmt.Errorf("cannot write %s key: %w", name, err)
+		}
+		if err := writeValue(k); err != nil {
+			return fmt.Errorf("cannot write %s value: %w", name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -236,6 +466,66 @@ func (zid ZDOID) String() string {
 	return fmt.Sprintf("%d:%d", zid.UserID, zid.ID)
 }
 
+// ParseZDOID parses a ZDOID in the format returned by ZDOID.String.
+func ParseZDOID(s string) (ZDOID, error) {
+	zid := ZDOID{}
+
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return zid, fmt.Errorf("invalid ZDOID %q", s)
+	}
+	userID, err := strconv.ParseInt(s[:i], 10, 64)
+	if err != nil {
+		return zid, fmt.Errorf("invalid ZDOID %q: %w", s, err)
+	}
+	id, err := strconv.ParseUint(s[i+1:], 10, 32)
+	if err != nil {
+		return zid, fmt.Errorf("invalid ZDOID %q: %w", s, err)
+	}
+
+	zid.UserID = userID
+	zid.ID = uint32(id)
+	return zid, nil
+}
+
+// zdoidJSON has the fields of ZDOID without its methods.
+type zdoidJSON ZDOID
+
+// MarshalJSON encodes the ZDOID as an object. It takes precedence over
+// MarshalText, which is only used for JSON object keys.
+func (zid ZDOID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(zdoidJSON(zid))
+}
+
+// UnmarshalJSON decodes a ZDOID encoded by MarshalJSON.
+func (zid *ZDOID) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*zdoidJSON)(zid))
+}
+
+// MarshalText encodes the ZDOID as returned by ZDOID.String, so that it can
+// be used as a JSON object key.
+func (zid ZDOID) MarshalText() ([]byte, error) {
+	return []byte(zid.String()), nil
+}
+
+// UnmarshalText decodes a ZDOID encoded by MarshalText.
+func (zid *ZDOID) UnmarshalText(text []byte) error {
+	v, err := ParseZDOID(string(text))
+	if err != nil {
+		return err
+	}
+	*zid = v
+	return nil
+}
+
+// Less reports whether zid sorts before other, by user ID then ID.
+func (zid ZDOID) Less(other ZDOID) bool {
+	if zid.UserID != other.UserID {
+		return zid.UserID < other.UserID
+	}
+	return zid.ID < other.ID
+}
+
 // Vector3 represents Unity.Vector3 type.
 type Vector3 struct {
 	X, Y, Z float32
@@ -1,8 +1,13 @@
 package vhpackage
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"sort"
 )
 
 type LocationInstance struct {
@@ -34,8 +39,10 @@ type World struct {
 	NetTime float64 `json:"net_time"`
 
 	// ZDO
-	ZDOs     []*ZDO           `json:"zdos"`
-	DeadZDOs map[string]int64 `json:"dead_zdos"`
+	ZDOManID int64           `json:"zdoman_id"`
+	NextUID  uint            `json:"next_uid"`
+	ZDOs     []*ZDO          `json:"zdos"`
+	DeadZDOs map[ZDOID]int64 `json:"dead_zdos"`
 
 	// ZoneSystem
 	GeneratedZones     []Vector2i         `json:"generated_zones"`
@@ -47,7 +54,7 @@ type World struct {
 
 	// RandEventSystem
 	EventTimer float32      `json:"event_timer"`
-	Event      *RandomEvent `json:"event,omitempty"` // Only version < 25
+	Event      *RandomEvent `json:"event,omitempty"` // Only version >= 25
 }
 
 func NewWorldFromFile(metaPath, dbPath string) (*World, error) {
@@ -133,16 +140,31 @@ func (w *World) readMetadata(pkg *ZPackage) error {
 }
 
 func (w *World) loadData(file string) error {
-	filedata, err := ioutil.ReadFile(file)
+	f, err := os.Open(file)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 
-	pkg := NewZPackageFromData(filedata)
-	return w.readData(pkg)
+	d, err := newWorldDecoder(NewZPackageReader(bufio.NewReader(f)), w)
+	if err != nil {
+		return err
+	}
+
+	for {
+		zdo, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		w.ZDOs = append(w.ZDOs, zdo)
+	}
 }
 
-func (w *World) readData(pkg *ZPackage) error {
+func (w *World) readHeader(pkg *ZPackage) error {
 	// World version
 	version, err := pkg.ReadInt()
 	if err != nil {
@@ -157,62 +179,55 @@ func (w *World) readData(pkg *ZPackage) error {
 		}
 	}
 
-	// ZDOMan
-	if err := w.readZDOMan(pkg); err != nil {
-		return fmt.Errorf("cannot read ZDOMan section: %w", err)
-	}
-
-	// ZoneSystem
-	if err := w.readZoneSystem(pkg); err != nil {
-		return fmt.Errorf("cannot read ZoneSystem section: %w", err)
-	}
-
-	// RandEventSystem
-	if err := w.readRandEventSystem(pkg); err != nil {
-		return fmt.Errorf("cannot read RandEventSystem section: %w", err)
-	}
-
 	return nil
 }
 
-func (w *World) readZDOMan(pkg *ZPackage) error {
+// readZDOManHeader reads ZDOMan metadata and returns the number of ZDOs.
+func (w *World) readZDOManHeader(pkg *ZPackage) (int, error) {
 	// ZDOMan metadata
-	_, err := pkg.ReadLong() // ID, Skipping this value
+	var err error
+	w.ZDOManID, err = pkg.ReadLong()
 	if err != nil {
-		return fmt.Errorf("cannot skip value: %w", err)
+		return 0, fmt.Errorf("cannot read ZDOMan ID: %w", err)
 	}
-	_, err = pkg.ReadUInt() // Next UID, skipping
+	w.NextUID, err = pkg.ReadUInt()
 	if err != nil {
-		return fmt.Errorf("cannot skip value: %w", err)
+		return 0, fmt.Errorf("cannot read next UID: %w", err)
 	}
 
 	// ZDOs
 	zdoCount, err := pkg.ReadInt()
 	if err != nil {
-		return fmt.Errorf("cannot read zdo count: %w", err)
+		return 0, fmt.Errorf("cannot read zdo count: %w", err)
 	}
-	for i := 0; i < zdoCount; i++ {
-		zdo := &ZDO{}
-		zdo.UID, err = pkg.ReadZDOID()
-		if err != nil {
-			return fmt.Errorf("(ZDO #%d) cannot read ZDOID: %w", i, err)
-		}
 
-		zdoPkg, err := pkg.ReadPackage()
-		if err != nil {
-			return fmt.Errorf("(ZDO #%d) cannot read ZDO: %w", i, err)
-		}
+	return zdoCount, nil
+}
 
-		err = zdo.LoadZDO(zdoPkg, w.Version)
-		if err != nil {
-			return fmt.Errorf("(ZDO #%d) cannot load ZDO: %w", i, err)
-		}
+func (w *World) readZDO(pkg *ZPackage) (*ZDO, error) {
+	var err error
 
-		w.ZDOs = append(w.ZDOs, zdo)
+	zdo := &ZDO{}
+	zdo.UID, err = pkg.ReadZDOID()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read ZDOID: %w", err)
 	}
 
-	// Dead ZDOs
-	w.DeadZDOs = make(map[string]int64)
+	zdoPkg, err := pkg.ReadPackage()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read ZDO: %w", err)
+	}
+
+	err = zdo.LoadZDO(zdoPkg, w.Version)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load ZDO: %w", err)
+	}
+
+	return zdo, nil
+}
+
+func (w *World) readDeadZDOs(pkg *ZPackage) error {
+	w.DeadZDOs = make(map[ZDOID]int64)
 	deadZdoCount, err := pkg.ReadInt()
 	if err != nil {
 		return fmt.Errorf("cannot read dead zdo count: %w", err)
@@ -227,7 +242,7 @@ func (w *World) readZDOMan(pkg *ZPackage) error {
 			return err
 		}
 
-		w.DeadZDOs[key.String()] = value
+		w.DeadZDOs[key] = value
 	}
 
 	return nil
@@ -314,6 +329,8 @@ func (w *World) readZoneSystem(pkg *ZPackage) error {
 				return fmt.Errorf("cannot read location generated: %w", err)
 			}
 		}
+
+		w.LocationInstances = append(w.LocationInstances, loc)
 	}
 
 	return nil
@@ -348,3 +365,248 @@ func (w *World) readRandEventSystem(pkg *ZPackage) error {
 
 	return nil
 }
+
+// SaveToFile writes the world metadata to metaPath (.fwl) and the world data
+// to dbPath (.db). Empty paths are skipped. Existing files are kept as
+// backups with the .bak extension.
+func (w *World) SaveToFile(metaPath, dbPath string) error {
+	if metaPath != "" {
+		if w.Metadata == nil {
+			return fmt.Errorf("world has no metadata")
+		}
+		data, err := w.Metadata.Data()
+		if err != nil {
+			return err
+		}
+		if err := writeFile(metaPath, data); err != nil {
+			return err
+		}
+	}
+
+	if dbPath != "" {
+		data, err := w.Data()
+		if err != nil {
+			return err
+		}
+		if err := writeFile(dbPath, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Data encodes the world metadata in the .fwl format.
+func (m *WorldMetadata) Data() ([]byte, error) {
+	meta := &bytes.Buffer{}
+	if err := m.writeMetadata(NewZPackageWriter(meta)); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewZPackageWriter(buf).WriteByteArray(meta.Bytes()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *WorldMetadata) writeMetadata(pkg *ZPackage) error {
+	if err := pkg.WriteInt(m.Version); err != nil {
+		return fmt.Errorf("Failed to write world version: %w", err)
+	}
+	if err := pkg.WriteString(m.Name); err != nil {
+		return fmt.Errorf("Failed to write world name: %w", err)
+	}
+	if err := pkg.WriteString(m.SeedName); err != nil {
+		return fmt.Errorf("Failed to write world seed name: %w", err)
+	}
+	if err := pkg.WriteInt(m.Seed); err != nil {
+		return fmt.Errorf("Failed to write world seed: %w", err)
+	}
+	if err := pkg.WriteLong(m.UID); err != nil {
+		return fmt.Errorf("Failed to write world UID: %w", err)
+	}
+	if m.Version >= 26 {
+		if err := pkg.WriteInt(m.WorldGenVersion); err != nil {
+			return fmt.Errorf("Failed to write world generation version: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Data encodes the world data in the .db format.
+func (w *World) Data() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := w.writeData(NewZPackageWriter(buf)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *World) writeData(pkg *ZPackage) error {
+	// World version
+	if err := pkg.WriteInt(w.Version); err != nil {
+		return fmt.Errorf("cannot write world version: %w", err)
+	}
+	// World uptime
+	if w.Version >= 4 {
+		if err := pkg.WriteDouble(w.NetTime); err != nil {
+			return fmt.Errorf("cannot write net time: %w", err)
+		}
+	}
+
+	// ZDOMan
+	if err := w.writeZDOMan(pkg); err != nil {
+		return fmt.Errorf("cannot write ZDOMan section: %w", err)
+	}
+
+	// ZoneSystem
+	if err := w.writeZoneSystem(pkg); err != nil {
+		return fmt.Errorf("cannot write ZoneSystem section: %w", err)
+	}
+
+	// RandEventSystem
+	if err := w.writeRandEventSystem(pkg); err != nil {
+		return fmt.Errorf("cannot write RandEventSystem section: %w", err)
+	}
+
+	return nil
+}
+
+func (w *World) writeZDOMan(pkg *ZPackage) error {
+	// ZDOMan metadata
+	if err := pkg.WriteLong(w.ZDOManID); err != nil {
+		return fmt.Errorf("cannot write ZDOMan ID: %w", err)
+	}
+	if err := pkg.WriteUInt(w.NextUID); err != nil {
+		return fmt.Errorf("cannot write next UID: %w", err)
+	}
+
+	// ZDOs
+	if err := pkg.WriteInt(len(w.ZDOs)); err != nil {
+		return fmt.Errorf("cannot write zdo count: %w", err)
+	}
+	zdoBuf := &bytes.Buffer{}
+	for i, zdo := range w.ZDOs {
+		if err := pkg.WriteZDOID(zdo.UID); err != nil {
+			return fmt.Errorf("(ZDO #%d) cannot write ZDOID: %w", i, err)
+		}
+
+		zdoBuf.Reset()
+		if err := zdo.SaveZDO(NewZPackageWriter(zdoBuf), w.Version); err != nil {
+			return fmt.Errorf("(ZDO #%d) cannot save ZDO: %w", i, err)
+		}
+		if err := pkg.WriteByteArray(zdoBuf.Bytes()); err != nil {
+			return fmt.Errorf("(ZDO #%d) cannot write ZDO: %w", i, err)
+		}
+	}
+
+	// Dead ZDOs
+	deadZDOs := make([]ZDOID, 0, len(w.DeadZDOs))
+	for zid := range w.DeadZDOs {
+		deadZDOs = append(deadZDOs, zid)
+	}
+	sort.Slice(deadZDOs, func(i, j int) bool { return deadZDOs[i].Less(deadZDOs[j]) })
+
+	if err := pkg.WriteInt(len(deadZDOs)); err != nil {
+		return fmt.Errorf("cannot write dead zdo count: %w", err)
+	}
+	for _, zid := range deadZDOs {
+		if err := pkg.WriteZDOID(zid); err != nil {
+			return err
+		}
+		if err := pkg.WriteLong(w.DeadZDOs[zid]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *World) writeZoneSystem(pkg *ZPackage) error {
+	if err := pkg.WriteInt(len(w.GeneratedZones)); err != nil {
+		return fmt.Errorf("cannot write generated zone count: %w", err)
+	}
+	for _, z := range w.GeneratedZones {
+		if err := pkg.WriteVector2i(z); err != nil {
+			return err
+		}
+	}
+
+	if w.Version < 13 {
+		return nil
+	}
+
+	if err := pkg.WriteInt(w.PGWVersion); err != nil {
+		return fmt.Errorf("cannot write world PGW version: %w", err)
+	}
+
+	if w.Version >= 21 {
+		if err := pkg.WriteInt(w.LocationVersion); err != nil {
+			return fmt.Errorf("cannot write location version: %w", err)
+		}
+	}
+
+	if w.Version >= 14 {
+		if err := pkg.WriteList(w.GlobalKeys); err != nil {
+			return fmt.Errorf("cannot write global keys: %w", err)
+		}
+	}
+
+	if w.Version < 18 {
+		return nil
+	}
+
+	if w.Version >= 20 {
+		if err := pkg.WriteBool(w.LocationsGenerated); err != nil {
+			return fmt.Errorf("cannot write locations generated: %w", err)
+		}
+	}
+
+	if err := pkg.WriteInt(len(w.LocationInstances)); err != nil {
+		return fmt.Errorf("cannot write location instances count: %w", err)
+	}
+
+	for _, loc := range w.LocationInstances {
+		if err := pkg.WriteString(loc.Name); err != nil {
+			return fmt.Errorf("cannot write location name: %w", err)
+		}
+		if err := pkg.WriteVector3(loc.Position); err != nil {
+			return fmt.Errorf("cannot write location position: %w", err)
+		}
+		if w.Version >= 19 {
+			if err := pkg.WriteBool(loc.Generated); err != nil {
+				return fmt.Errorf("cannot write location generated: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *World) writeRandEventSystem(pkg *ZPackage) error {
+	if err := pkg.WriteSingle(w.EventTimer); err != nil {
+		return fmt.Errorf("cannot write event timer: %w", err)
+	}
+
+	if w.Version < 25 {
+		return nil
+	}
+
+	evt := w.Event
+	if evt == nil {
+		evt = &RandomEvent{}
+	}
+	if err := pkg.WriteString(evt.Text); err != nil {
+		return fmt.Errorf("cannot write random event text: %w", err)
+	}
+	if err := pkg.WriteSingle(evt.Time); err != nil {
+		return fmt.Errorf("cannot write random event time: %w", err)
+	}
+	if err := pkg.WriteVector3(evt.Position); err != nil {
+		return fmt.Errorf("cannot write random event position: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package vhpackage
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ChangeMoved is used for ZDOs whose position changed between two worlds.
+const ChangeMoved ChangeKind = "moved"
+
+// ZDOSummary is a compact description of a ZDO attached to world changes.
+type ZDOSummary struct {
+	UID      ZDOID    `json:"uid"`
+	Prefab   int      `json:"prefab"`
+	Sector   Vector2i `json:"sector"`
+	Position Vector3  `json:"position"`
+}
+
+// zdoDigest holds what is needed to compare a ZDO without keeping it in memory.
+type zdoDigest struct {
+	summary ZDOSummary
+	sizes   [7]int
+	hashes  [7]uint64
+}
+
+var zdoPropertyNames = [7]string{"Floats", "Vectors", "Quaternions", "Ints", "Longs", "Strings", "ByteArrays"}
+
+func newZDODigest(zdo *ZDO) zdoDigest {
+	dg := zdoDigest{
+		summary: ZDOSummary{
+			UID:      zdo.UID,
+			Prefab:   zdo.Prefab,
+			Sector:   zdo.Sector,
+			Position: zdo.Position,
+		},
+	}
+
+	keys := make([]int, 0, len(zdo.Floats))
+	for k := range zdo.Floats {
+		keys = append(keys, k)
+	}
+	dg.sizes[0], dg.hashes[0] = len(keys), hashProperties(keys, func(w io.Writer, k int) {
+		binary.Write(w, binary.LittleEndian, zdo.Floats[k])
+	})
+
+	keys = keys[:0]
+	for k := range zdo.Vectors {
+		keys = append(keys, k)
+	}
+	dg.sizes[1], dg.hashes[1] = len(keys), hashProperties(keys, func(w io.Writer, k int) {
+		binary.Write(w, binary.LittleEndian, zdo.Vectors[k])
+	})
+
+	keys = keys[:0]
+	for k := range zdo.Quaternions {
+		keys = append(keys, k)
+	}
+	dg.sizes[2], dg.hashes[2] = len(keys), hashProperties(keys, func(w io.Writer, k int) {
+		binary.Write(w, binary.LittleEndian, zdo.Quaternions[k])
+	})
+
+	keys = keys[:0]
+	for k := range zdo.Ints {
+		keys = append(keys, k)
+	}
+	dg.sizes[3], dg.hashes[3] = len(keys), hashProperties(keys, func(w io.Writer, k int) {
+		binary.Write(w, binary.LittleEndian, int32(zdo.Ints[k]))
+	})
+
+	keys = keys[:0]
+	for k := range zdo.Longs {
+		keys = append(keys, k)
+	}
+	dg.sizes[4], dg.hashes[4] = len(keys), hashProperties(keys, func(w io.Writer, k int) {
+		binary.Write(w, binary.LittleEndian, zdo.Longs[k])
+	})
+
+	keys = keys[:0]
+	for k := range zdo.Strings {
+		keys = append(keys, k)
+	}
+	dg.sizes[5], dg.hashes[5] = len(keys), hashProperties(keys, func(w io.Writer, k int) {
+		binary.Write(w, binary.LittleEndian, int32(len(zdo.Strings[k])))
+		io.WriteString(w, zdo.Strings[k])
+	})
+
+	keys = keys[:0]
+	for k := range zdo.ByteArrays {
+		keys = append(keys, k)
+	}
+	dg.sizes[6], dg.hashes[6] = len(keys), hashProperties(keys, func(w io.Writer, k int) {
+		binary.Write(w, binary.LittleEndian, int32(len(zdo.ByteArrays[k])))
+		w.Write(zdo.ByteArrays[k])
+	})
+
+	return dg
+}
+
+// hashProperties hashes a property map given its keys and a function
+// writing the value of a key.
+func hashProperties(keys []int, writeValue func(w io.Writer, key int)) uint64 {
+	sort.Ints(keys)
+
+	h := fnv.New64a()
+	for _, k := range keys {
+		binary.Write(h, binary.LittleEndian, int32(k))
+		writeValue(h, k)
+	}
+	return h.Sum64()
+}
+
+// DiffWorlds returns the changes needed to go from world a to world b.
+// ZDOs are matched by ZDOID and reported as added, removed, moved or with
+// modified property maps. Modified property maps report their number of entries.
+func DiffWorlds(a, b *World) []Change {
+	wd := newWorldDiffer()
+	for _, zdo := range a.ZDOs {
+		wd.indexOld(zdo)
+	}
+	for _, zdo := range b.ZDOs {
+		wd.compareNew(zdo)
+	}
+	return wd.finish(a, b)
+}
+
+// DiffWorldData returns the changes between two world databases (.db),
+// decoding them as streams. Only a digest of each ZDO of the first world
+// is kept in memory.
+func DiffWorldData(a, b io.Reader) ([]Change, error) {
+	wd := newWorldDiffer()
+
+	da, err := NewWorldDecoder(a)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		zdo, err := da.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		wd.indexOld(zdo)
+	}
+
+	db, err := NewWorldDecoder(b)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		zdo, err := db.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		wd.compareNew(zdo)
+	}
+
+	return wd.finish(da.World(), db.World()), nil
+}
+
+type worldDiffer struct {
+	old     map[ZDOID]zdoDigest
+	changes []Change
+}
+
+func newWorldDiffer() *worldDiffer {
+	return &worldDiffer{
+		old: make(map[ZDOID]zdoDigest),
+	}
+}
+
+func (wd *worldDiffer) indexOld(zdo *ZDO) {
+	wd.old[zdo.UID] = newZDODigest(zdo)
+}
+
+func (wd *worldDiffer) add(kind ChangeKind, path string, old, new interface{}, zdo ZDOSummary) {
+	wd.changes = append(wd.changes, Change{Kind: kind, Path: path, Old: old, New: new, ZDO: &zdo})
+}
+
+func (wd *worldDiffer) compareNew(zdo *ZDO) {
+	n := newZDODigest(zdo)
+	path := "ZDOs/" + zdo.UID.String()
+
+	o, ok := wd.old[zdo.UID]
+	if !ok {
+		wd.add(ChangeAdded, path, nil, n.summary.Position, n.summary)
+		return
+	}
+	delete(wd.old, zdo.UID)
+
+	if o.summary.Position != n.summary.Position {
+		wd.add(ChangeMoved, path, o.summary.Position, n.summary.Position, n.summary)
+	}
+	if o.summary.Prefab != n.summary.Prefab {
+		wd.add(ChangeModified, path+"/Prefab", o.summary.Prefab, n.summary.Prefab, n.summary)
+	}
+	for i, name := range zdoPropertyNames {
+		if o.hashes[i] != n.hashes[i] || o.sizes[i] != n.sizes[i] {
+			wd.add(ChangeModified, path+"/"+name, o.sizes[i], n.sizes[i], n.summary)
+		}
+	}
+}
+
+func (wd *worldDiffer) finish(a, b *World) []Change {
+	// ZDOs left in the index are missing from the new world.
+	removed := make([]ZDOID, 0, len(wd.old))
+	for uid := range wd.old {
+		removed = append(removed, uid)
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Less(removed[j]) })
+	for _, uid := range removed {
+		o := wd.old[uid]
+		wd.add(ChangeRemoved, "ZDOs/"+uid.String(), o.summary.Position, nil, o.summary)
+	}
+
+	d := &differ{}
+	if a.Metadata != nil && b.Metadata != nil {
+		d.metadata(a.Metadata, b.Metadata)
+	}
+	d.value("Version", a.Version, b.Version)
+	d.value("NetTime", a.NetTime, b.NetTime)
+	d.value("DeadZDOs", len(a.DeadZDOs), len(b.DeadZDOs))
+	d.value("GeneratedZones", len(a.GeneratedZones), len(b.GeneratedZones))
+	d.set("GlobalKeys", a.GlobalKeys, b.GlobalKeys)
+
+	return append(d.changes, wd.changes...)
+}
+
+// DiffWorldMetadata returns the changes needed to go from world metadata a
+// to world metadata b.
+func DiffWorldMetadata(a, b *WorldMetadata) []Change {
+	d := &differ{}
+	d.metadata(a, b)
+	return d.changes
+}
+
+func (d *differ) metadata(a, b *WorldMetadata) {
+	d.value("Metadata/Name", a.Name, b.Name)
+	d.value("Metadata/SeedName", a.SeedName, b.SeedName)
+	d.value("Metadata/Seed", a.Seed, b.Seed)
+	d.value("Metadata/UID", a.UID, b.UID)
+	d.value("Metadata/WorldGenVersion", a.WorldGenVersion, b.WorldGenVersion)
+}
+
+// ZDOChangeSummary counts changed ZDOs by kind of change.
+type ZDOChangeSummary map[ChangeKind]int
+
+// SummarizeZDOChanges groups ZDO changes with the key returned by group and
+// counts the changed ZDOs by kind of change. A ZDO with several changes of
+// the same kind, such as modifications of several property maps, is counted
+// once. Changes not related to a ZDO are ignored.
+func SummarizeZDOChanges(changes []Change, group func(ZDOSummary) string) map[string]ZDOChangeSummary {
+	type zdoChange struct {
+		key  string
+		kind ChangeKind
+		uid  ZDOID
+	}
+	seen := make(map[zdoChange]bool)
+
+	summary := make(map[string]ZDOChangeSummary)
+	for _, c := range changes {
+		if c.ZDO == nil {
+			continue
+		}
+
+		key := group(*c.ZDO)
+		zc := zdoChange{key, c.Kind, c.ZDO.UID}
+		if seen[zc] {
+			continue
+		}
+		seen[zc] = true
+
+		if summary[key] == nil {
+			summary[key] = make(ZDOChangeSummary)
+		}
+		summary[key][c.Kind]++
+	}
+	return summary
+}
+
+// ByPrefab groups ZDO changes by prefab hash.
+func ByPrefab(zdo ZDOSummary) string {
+	return strconv.Itoa(zdo.Prefab)
+}
+
+// BySector groups ZDO changes by sector coordinates.
+func BySector(zdo ZDOSummary) string {
+	return strconv.Itoa(int(zdo.Sector.X)) + "," + strconv.Itoa(int(zdo.Sector.Y))
+}
@@ -0,0 +1,91 @@
+package vhpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+)
+
+// WorldVersion is the latest world format version known by this package.
+const WorldVersion = 27
+
+// NewWorldFromJSONFile reads a world from a JSON file as produced by
+// encoding a World.
+func NewWorldFromJSONFile(file string) (*World, error) {
+	filedata, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWorldFromJSON(filedata)
+}
+
+// NewWorldFromJSON decodes a world from JSON.
+// Unknown fields are rejected and the world is validated so that it can
+// be written back in the save file formats.
+func NewWorldFromJSON(data []byte) (*World, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	w := &World{}
+	if err := dec.Decode(w); err != nil {
+		return nil, fmt.Errorf("cannot decode world: %w", err)
+	}
+
+	return w, w.Validate()
+}
+
+// HasData reports whether the world holds world data (.db) and not only metadata.
+func (w *World) HasData() bool {
+	return w.Version != 0
+}
+
+// Validate checks that the world can be written in the save file formats.
+func (w *World) Validate() error {
+	if w.Metadata != nil {
+		if err := w.Metadata.Validate(); err != nil {
+			return fmt.Errorf("invalid world metadata: %w", err)
+		}
+	}
+
+	if !w.HasData() {
+		return nil
+	}
+	if w.Version < 0 || w.Version > WorldVersion {
+		return fmt.Errorf("unsupported world version %d", w.Version)
+	}
+
+	for i, zdo := range w.ZDOs {
+		if zdo == nil {
+			return fmt.Errorf("ZDO #%d is empty", i)
+		}
+		if err := zdo.validate(w.Version); err != nil {
+			return fmt.Errorf("invalid ZDO %s: %w", zdo.UID, err)
+		}
+	}
+
+	return nil
+}
+
+func (zdo *ZDO) validate(version int) error {
+	counts := map[string]int{
+		"floats":      len(zdo.Floats),
+		"vector3s":    len(zdo.Vectors),
+		"quaternions": len(zdo.Quaternions),
+		"ints":        len(zdo.Ints),
+		"longs":       len(zdo.Longs),
+		"strings":     len(zdo.Strings),
+		"byte arrays": len(zdo.ByteArrays),
+	}
+	for name, count := range counts {
+		if count > math.MaxUint8 {
+			return fmt.Errorf("too many %s: %d", name, count)
+		}
+	}
+	if version < 27 && len(zdo.ByteArrays) > 0 {
+		return fmt.Errorf("byte arrays are not supported by world version %d", version)
+	}
+	return nil
+}
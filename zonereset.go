@@ -0,0 +1,62 @@
+package vhpackage
+
+// ZoneResetResult reports what a zone reset changed.
+type ZoneResetResult struct {
+	Zones          int `json:"zones"`
+	RemovedZDOs    int `json:"removed_zdos"`
+	KeptBuildings  int `json:"kept_buildings"`
+	ResetLocations int `json:"reset_locations"`
+}
+
+// ZoneRegion is a rectangle of zones, bounds included.
+type ZoneRegion struct {
+	Min Vector2i `json:"min"`
+	Max Vector2i `json:"max"`
+}
+
+// Contains returns true if zone is inside the region.
+func (r ZoneRegion) Contains(zone Vector2i) bool {
+	return zone.X >= r.Min.X && zone.X <= r.Max.X && zone.Y >= r.Min.Y && zone.Y <= r.Max.Y
+}
+
+// ResetZones makes the game generate the zones selected by reset again:
+// they are removed from the generated zones along with the ZDOs of their
+// sectors, and location instances in them are marked as not generated.
+// Player built pieces are kept when keepBuildings is true.
+func (w *World) ResetZones(reset func(zone Vector2i) bool, keepBuildings bool) ZoneResetResult {
+	result := ZoneResetResult{}
+
+	generated := w.GeneratedZones[:0]
+	for _, zone := range w.GeneratedZones {
+		if reset(zone) {
+			result.Zones++
+			continue
+		}
+		generated = append(generated, zone)
+	}
+	w.GeneratedZones = generated
+
+	zdos := w.ZDOs[:0]
+	for _, zdo := range w.ZDOs {
+		if !reset(zdo.Sector) {
+			zdos = append(zdos, zdo)
+			continue
+		}
+		if keepBuildings && zdo.Category().IsPiece() {
+			result.KeptBuildings++
+			zdos = append(zdos, zdo)
+			continue
+		}
+		result.RemovedZDOs++
+	}
+	w.ZDOs = zdos
+
+	for i, loc := range w.LocationInstances {
+		if loc.Generated && reset(ZoneOf(loc.Position)) {
+			w.LocationInstances[i].Generated = false
+			result.ResetLocations++
+		}
+	}
+
+	return result
+}
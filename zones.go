@@ -0,0 +1,211 @@
+package vhpackage
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// ZoneOf returns the zone containing a world position.
+func ZoneOf(pos Vector3) Vector2i {
+	return Vector2i{
+		X: int32(math.Floor(float64(pos.X+ZoneSize/2) / ZoneSize)),
+		Y: int32(math.Floor(float64(pos.Z+ZoneSize/2) / ZoneSize)),
+	}
+}
+
+// ZoneDistance is the distance of a zone to the closest zone with player
+// built pieces, in zones. Distance is -1 when the world has no building.
+type ZoneDistance struct {
+	Zone     Vector2i `json:"zone"`
+	Distance int      `json:"distance"`
+}
+
+// ZoneReport summarizes the generated zones of a world.
+type ZoneReport struct {
+	GeneratedZones int `json:"generated_zones"`
+	// Area is the generated area in square kilometers.
+	Area       float64 `json:"area_km2"`
+	BuiltZones int     `json:"built_zones"`
+	// Outlying lists generated zones far from any player built piece,
+	// farthest first.
+	Outlying []ZoneDistance `json:"outlying"`
+}
+
+// ZoneReport reports the generated zones coverage. Zones at least
+// minDistance zones away from any player built piece are listed as outlying.
+func (w *World) ZoneReport(minDistance int) *ZoneReport {
+	built := w.builtZones()
+
+	r := &ZoneReport{
+		GeneratedZones: len(w.GeneratedZones),
+		Area:           float64(len(w.GeneratedZones)) * ZoneSize * ZoneSize / 1e6,
+		BuiltZones:     len(built),
+		Outlying:       []ZoneDistance{},
+	}
+
+	distances := zoneDistances(w.GeneratedZones, built)
+	for _, zone := range w.GeneratedZones {
+		d := distances[zone]
+		if d < 0 || d >= minDistance {
+			r.Outlying = append(r.Outlying, ZoneDistance{Zone: zone, Distance: d})
+		}
+	}
+	sort.SliceStable(r.Outlying, func(i, j int) bool {
+		di, dj := r.Outlying[i].Distance, r.Outlying[j].Distance
+		if di < 0 || dj < 0 {
+			return di < dj
+		}
+		return di > dj
+	})
+
+	return r
+}
+
+// builtZones returns the zones containing player built pieces.
+func (w *World) builtZones() []Vector2i {
+	seen := make(map[Vector2i]bool)
+	zones := []Vector2i{}
+	for _, zdo := range w.ZDOs {
+		if !zdo.Category().IsPiece() {
+			continue
+		}
+		zone := ZoneOf(zdo.Position)
+		if !seen[zone] {
+			seen[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	return zones
+}
+
+// zoneDistances returns the Chebyshev distance from each zone to the
+// closest zone of targets, or -1 if there is no target. Distances are
+// computed at once with a breadth first search from every target over the
+// bounding box of zones and targets, which contains the shortest paths.
+func zoneDistances(zones, targets []Vector2i) map[Vector2i]int {
+	distances := make(map[Vector2i]int, len(zones))
+	if len(targets) == 0 {
+		for _, zone := range zones {
+			distances[zone] = -1
+		}
+		return distances
+	}
+
+	min, max := targets[0], targets[0]
+	for _, l := range [][]Vector2i{zones, targets} {
+		for _, z := range l {
+			min.X, min.Y = minInt32(min.X, z.X), minInt32(min.Y, z.Y)
+			max.X, max.Y = maxInt32(max.X, z.X), maxInt32(max.Y, z.Y)
+		}
+	}
+	width, height := int(max.X-min.X)+1, int(max.Y-min.Y)+1
+	index := func(z Vector2i) int { return int(z.Y-min.Y)*width + int(z.X-min.X) }
+
+	grid := make([]int, width*height)
+	for i := range grid {
+		grid[i] = -1
+	}
+	queue := make([]Vector2i, 0, len(targets))
+	for _, t := range targets {
+		if grid[index(t)] < 0 {
+			grid[index(t)] = 0
+			queue = append(queue, t)
+		}
+	}
+	for len(queue) > 0 {
+		z := queue[0]
+		queue = queue[1:]
+		d := grid[index(z)]
+		for dy := int32(-1); dy <= 1; dy++ {
+			for dx := int32(-1); dx <= 1; dx++ {
+				n := Vector2i{X: z.X + dx, Y: z.Y + dy}
+				if n.X < min.X || n.X > max.X || n.Y < min.Y || n.Y > max.Y || grid[index(n)] >= 0 {
+					continue
+				}
+				grid[index(n)] = d + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	for _, zone := range zones {
+		distances[zone] = grid[index(zone)]
+	}
+	return distances
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// ZoneGrowth returns the zones generated in world b but not in world a,
+// and the zones of a missing from b.
+func ZoneGrowth(a, b *World) (added, removed []Vector2i) {
+	inA := make(map[Vector2i]bool, len(a.GeneratedZones))
+	for _, zone := range a.GeneratedZones {
+		inA[zone] = true
+	}
+	inB := make(map[Vector2i]bool, len(b.GeneratedZones))
+	for _, zone := range b.GeneratedZones {
+		inB[zone] = true
+		if !inA[zone] {
+			added = append(added, zone)
+		}
+	}
+	for _, zone := range a.GeneratedZones {
+		if !inB[zone] {
+			removed = append(removed, zone)
+		}
+	}
+	return added, removed
+}
+
+// RenderZoneHeatmap draws generated zones colored by their number of ZDOs,
+// from green for empty zones to red for the densest one.
+func (w *World) RenderZoneHeatmap(opts RenderOptions) (*image.RGBA, error) {
+	counts := make(map[Vector2i]int, len(w.GeneratedZones))
+	for _, zdo := range w.ZDOs {
+		counts[ZoneOf(zdo.Position)]++
+	}
+	max := 0
+	for _, zone := range w.GeneratedZones {
+		if counts[zone] > max {
+			max = counts[zone]
+		}
+	}
+
+	r, err := newRenderer(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, zone := range w.GeneratedZones {
+		r.zone(zone, heatColor(counts[zone], max))
+	}
+	return r.img, nil
+}
+
+// heatColor returns a color from green (0) to red (max), on a log scale.
+func heatColor(n, max int) color.RGBA {
+	if n == 0 || max == 0 {
+		return renderZone
+	}
+	t := math.Log1p(float64(n)) / math.Log1p(float64(max))
+	return color.RGBA{
+		R: uint8(80 + 175*t),
+		G: uint8(200 * (1 - t)),
+		B: 40,
+		A: 255,
+	}
+}
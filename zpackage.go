@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"unicode/utf8"
 )
 
 // ZPackage utility class to read and write in binary format.
@@ -55,12 +56,38 @@ func (p *ZPackage) ReadBool() (bool, error) {
 	return b, p.read(&b)
 }
 
-func (p *ZPackage) ReadChar() (uint8, error) {
-	var c uint8
-	return c, p.read(&c)
+// ReadChar reads a .NET char, encoded in UTF-8 on 1 to 3 bytes.
+func (p *ZPackage) ReadChar() (uint16, error) {
+	b, err := p.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	switch {
+	case b < 0x80:
+		return uint16(b), nil
+	case b&0xE0 == 0xC0:
+		n = 2
+	case b&0xF0 == 0xE0:
+		n = 3
+	default:
+		return 0, fmt.Errorf("invalid char first byte 0x%02x", b)
+	}
+
+	data := make([]byte, n)
+	data[0] = b
+	if err := p.read(data[1:]); err != nil {
+		return 0, err
+	}
+	r, size := utf8.DecodeRune(data)
+	if r == utf8.RuneError || size != n {
+		return 0, fmt.Errorf("invalid char encoding % x", data)
+	}
+	return uint16(r), nil
 }
 
-func (p *ZPackage) ReadByte() (uint8, error) {
+func (p *ZPackage) ReadByte() (byte, error) {
 	var b uint8
 	return b, p.read(&b)
 }
@@ -223,7 +250,33 @@ func (p *ZPackage) read(data interface{}) error {
 	return binary.Read(p.r, binary.LittleEndian, data)
 }
 
-func (p *ZPackage) WriteByte(b uint8) error {
+func (p *ZPackage) WriteZDOID(zdoid ZDOID) error {
+	if err := p.write(zdoid.UserID); err != nil {
+		return err
+	}
+	return p.write(zdoid.ID)
+}
+
+func (p *ZPackage) WriteBool(b bool) error {
+	return p.write(b)
+}
+
+// WriteChar writes a .NET char, encoded in UTF-8 on 1 to 3 bytes.
+// Surrogate halves cannot be encoded.
+func (p *ZPackage) WriteChar(c uint16) error {
+	r := rune(c)
+	if !utf8.ValidRune(r) {
+		return fmt.Errorf("cannot encode char 0x%04x", c)
+	}
+	data := make([]byte, utf8.UTFMax)
+	return p.write(data[:utf8.EncodeRune(data, r)])
+}
+
+func (p *ZPackage) WriteByte(b byte) error {
+	return p.write(b)
+}
+
+func (p *ZPackage) WriteSByte(b int8) error {
 	return p.write(b)
 }
 
@@ -231,12 +284,24 @@ func (p *ZPackage) WriteInt(n int) error {
 	return p.write(int32(n))
 }
 
+func (p *ZPackage) WriteUInt(n uint) error {
+	return p.write(uint32(n))
+}
+
+func (p *ZPackage) WriteLong(l int64) error {
+	return p.write(l)
+}
+
+func (p *ZPackage) WriteULong(l uint64) error {
+	return p.write(l)
+}
+
 func (p *ZPackage) WriteSingle(f float32) error {
 	return p.write(f)
 }
 
-func (p *ZPackage) WriteBool(b bool) error {
-	return p.write(b)
+func (p *ZPackage) WriteDouble(d float64) error {
+	return p.write(d)
 }
 
 func (p *ZPackage) WriteString(s string) error {
@@ -251,6 +316,57 @@ func (p *ZPackage) WriteString(s string) error {
 	return p.write([]byte(s))
 }
 
+// WriteByteArray writes data with its length (int32) as header.
+func (p *ZPackage) WriteByteArray(data []byte) error {
+	if err := p.write(int32(len(data))); err != nil {
+		return err
+	}
+	return p.write(data)
+}
+
+func (p *ZPackage) WriteVector3(v Vector3) error {
+	return p.write(v)
+}
+
+func (p *ZPackage) WriteVector2i(v Vector2i) error {
+	return p.write(v)
+}
+
+func (p *ZPackage) WriteQuaternion(q Quaternion) error {
+	return p.write(q)
+}
+
+// WriteList writes a list with its count (int32) as header.
+// It is the counterpart of ReadIntoList.
+func (p *ZPackage) WriteList(l interface{}) error {
+	switch x := l.(type) {
+	case []string:
+		if err := p.WriteInt(len(x)); err != nil {
+			return err
+		}
+		for _, s := range x {
+			if err := p.WriteString(s); err != nil {
+				return err
+			}
+		}
+
+	case []int:
+		if err := p.WriteInt(len(x)); err != nil {
+			return err
+		}
+		int32s := make([]int32, len(x))
+		for i, v := range x {
+			int32s[i] = int32(v)
+		}
+		return p.write(int32s)
+
+	default:
+		return fmt.Errorf("cannot write list of type %T", l)
+	}
+
+	return nil
+}
+
 func (p *ZPackage) write(v interface{}) error {
 	return binary.Write(p.w, binary.LittleEndian, v)
 }